@@ -10,14 +10,15 @@ import (
 type Account struct {
 	ID             *string   `json:"id"`
 	Name           string    `json:"name"`
-	Balance        float64   `json:"balance"`
-	InitialBalance *float64  `json:"initial_balance"`
+	Balance        Amount    `json:"balance"`
+	InitialBalance *Amount   `json:"initial_balance"`
 	Currency       *Currency `json:"currency"`
 	Median         *Median   `json:"median"`
 	Status         string    `json:"status"`
 	Order          int       `json:"order"`
 	Modified       *string   `json:"modified"`
 	Goal           *Goal     `json:"goal"`
+	Deleted        bool      `json:"deleted,omitempty"`
 }
 
 // AccountQueryParams represents a struct of parameters usable
@@ -42,7 +43,7 @@ func (a *AccountQueryParams) getQueryString() string {
 	}
 
 	if !a.Since.IsZero() {
-		v.Set("since", a.Since.Format("2006-01-02T15:04:05Z"))
+		v.Set("since", a.Since.Format(sinceFormat))
 	}
 
 	if a.Status != "" {
@@ -2,10 +2,13 @@ package toshl_test
 
 import (
 	"encoding/json"
+	"errors"
 	"testing"
 
 	"github.com/Philanthropists/toshl-go"
+	"github.com/Philanthropists/toshl-go/toshltest"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestAccountDecode(t *testing.T) {
@@ -131,3 +134,85 @@ func TestAccountDecodeError(t *testing.T) {
 	err := json.Unmarshal(accountJSON, &account)
 	assert.NotNil(t, err)
 }
+
+func TestUpdateAccountFuncRetriesOnConflict(t *testing.T) {
+	server := toshltest.NewServer()
+	defer server.Close()
+
+	client := server.NewClient()
+
+	id, err := client.CreateAccount(toshl.CreateAccountParams{
+		Name:     "Checking",
+		Currency: toshl.Currency{Code: "USD"},
+	})
+	require.NoError(t, err)
+
+	calls := 0
+	account, err := client.UpdateAccountFunc(id, func(a *toshl.Account) error {
+		calls++
+
+		if calls == 1 {
+			// Someone else updates the account in between
+			other, err := client.GetAccount(id)
+			require.NoError(t, err)
+
+			other.Status = "inactive"
+			require.NoError(t, client.UpdateAccount(other))
+		}
+
+		a.Name = "Main"
+		return nil
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, calls)
+	assert.Equal(t, "Main", account.Name)
+	assert.Equal(t, "inactive", account.Status)
+}
+
+func TestUpdateAccountFuncGivesUp(t *testing.T) {
+	server := toshltest.NewServer()
+	defer server.Close()
+
+	client := server.NewClient()
+
+	id, err := client.CreateAccount(toshl.CreateAccountParams{
+		Name:     "Checking",
+		Currency: toshl.Currency{Code: "USD"},
+	})
+	require.NoError(t, err)
+
+	calls := 0
+	_, err = client.UpdateAccountFunc(id, func(a *toshl.Account) error {
+		calls++
+
+		other, err := client.GetAccount(id)
+		require.NoError(t, err)
+		require.NoError(t, client.UpdateAccount(other))
+
+		return nil
+	})
+
+	assert.True(t, toshl.IsConflict(err), "err = %v", err)
+	assert.Equal(t, 4, calls)
+}
+
+func TestUpdateAccountFuncStopsOnError(t *testing.T) {
+	server := toshltest.NewServer()
+	defer server.Close()
+
+	client := server.NewClient()
+
+	id, err := client.CreateAccount(toshl.CreateAccountParams{
+		Name:     "Checking",
+		Currency: toshl.Currency{Code: "USD"},
+	})
+	require.NoError(t, err)
+
+	errAbort := errors.New("abort")
+	_, err = client.UpdateAccountFunc(id, func(a *toshl.Account) error {
+		return errAbort
+	})
+
+	assert.ErrorIs(t, err, errAbort)
+}
@@ -11,10 +11,10 @@ import (
 type Budget struct {
 	ID         string     `json:"id"`
 	Name       string     `json:"name"`
-	Limit      int        `json:"limit"`
-	Amount     float64    `json:"amount"`
-	Planned    float64    `json:"planned"`
-	Median     int        `json:"median"`
+	Limit      Amount     `json:"limit"`
+	Amount     Amount     `json:"amount"`
+	Planned    Amount     `json:"planned"`
+	Median     Amount     `json:"median"`
 	Currency   Currency   `json:"currency"`
 	From       string     `json:"from"`
 	To         string     `json:"to"`
@@ -25,6 +25,7 @@ type Budget struct {
 	Type       string     `json:"type"`
 	Order      int        `json:"order"`
 	Categories []string   `json:"categories"`
+	Deleted    bool       `json:"deleted,omitempty"`
 }
 
 // BudgetQueryParams represents a struct of parameters usable
@@ -58,7 +59,7 @@ func (b *BudgetQueryParams) getQueryString() string {
 	}
 
 	if !b.Since.IsZero() {
-		v.Set("since", b.Since.Format("2006-01-02T15:04:05Z"))
+		v.Set("since", b.Since.Format(sinceFormat))
 	}
 
 	if !b.From.IsZero() {
@@ -107,3 +108,44 @@ func (b *BudgetQueryParams) getQueryString() string {
 
 	return v.Encode()
 }
+
+// isRecurring reports whether the budget is part of a recurring series
+func (b *Budget) isRecurring() bool {
+	return b.Recurrence.Frequency != ""
+}
+
+// BudgetsOrderParams describes the order we want for the budgets
+type BudgetsOrderParams struct {
+	Order []string `json:"order"`
+}
+
+// BudgetHistoryParams represents a struct of parameters usable
+// to List the past iterations of a Budget
+type BudgetHistoryParams struct {
+	Page    int
+	PerPage int
+	From    time.Time
+	To      time.Time
+}
+
+func (b *BudgetHistoryParams) getQueryString() string {
+	v := url.Values{}
+
+	if b.Page > 0 {
+		v.Set("page", strconv.Itoa(b.Page))
+	}
+
+	if b.PerPage > 0 {
+		v.Set("per_page", strconv.Itoa(b.PerPage))
+	}
+
+	if !b.From.IsZero() {
+		v.Set("from", b.From.Format("2006-01-02"))
+	}
+
+	if !b.To.IsZero() {
+		v.Set("to", b.To.Format("2006-01-02"))
+	}
+
+	return v.Encode()
+}
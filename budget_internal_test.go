@@ -34,3 +34,16 @@ func TestBudgetGetQueryString(t *testing.T) {
 			`search=search_term&since=2016-11-06T13%3A28%3A00Z&`+
 			`tags=tag1%2Ctag2&to=2016-11-06`)
 }
+
+func TestBudgetHistoryGetQueryString(t *testing.T) {
+	b := BudgetHistoryParams{
+		Page:    2,
+		PerPage: 10,
+		From:    time.Date(2016, 1, 1, 0, 0, 0, 0, time.Local),
+		To:      time.Date(2016, 6, 30, 0, 0, 0, 0, time.Local),
+	}
+
+	assert.Equal(t,
+		b.getQueryString(),
+		`from=2016-01-01&page=2&per_page=10&to=2016-06-30`)
+}
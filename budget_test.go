@@ -2,6 +2,7 @@ package toshl_test
 
 import (
 	"encoding/json"
+	"net/http"
 	"testing"
 
 	toshl "github.com/Philanthropists/toshl-go"
@@ -46,3 +47,61 @@ func TestBudgetDecode(t *testing.T) {
 
 	assert.Nil(t, err)
 }
+
+func TestClientUpdateBudget(t *testing.T) {
+	c, requests := newTestClient(t, http.StatusOK, `{
+        "id": "42",
+        "name": "Monthly budget",
+        "limit": 1200,
+        "modified": "2013-06-28T14:14:03Z"
+    }`)
+
+	budget := &toshl.Budget{
+		ID:         "42",
+		Name:       "Monthly budget",
+		Limit:      toshl.NewAmount(1200, 0),
+		Recurrence: toshl.Recurrence{Frequency: "monthly", Interval: 1},
+	}
+
+	err := c.UpdateBudget(budget, toshl.RepeatAll)
+	assert.Nil(t, err)
+	assert.Equal(t, "PUT", (*requests)[0].Method)
+	assert.Equal(t, "/budgets/42", (*requests)[0].Path)
+	assert.Equal(t, "update=all", (*requests)[0].Query)
+	assert.Equal(t, "2013-06-28T14:14:03Z", budget.Modified)
+}
+
+func TestClientDeleteBudgetNotRecurring(t *testing.T) {
+	c, requests := newTestClient(t, http.StatusNoContent, "")
+
+	err := c.DeleteBudget(&toshl.Budget{ID: "42"}, toshl.RepeatAll)
+	assert.Nil(t, err)
+	assert.Equal(t, "DELETE", (*requests)[0].Method)
+	assert.Equal(t, "", (*requests)[0].Query)
+}
+
+func TestClientBudgetHistory(t *testing.T) {
+	c, requests := newTestClient(t, http.StatusOK, `[
+        {"id": "42", "from": "2013-01-01", "to": "2013-01-31"},
+        {"id": "43", "from": "2013-02-01", "to": "2013-02-28"}
+    ]`)
+
+	budgets, err := c.BudgetHistory("42", &toshl.BudgetHistoryParams{
+		PerPage: 2,
+	})
+	assert.Nil(t, err)
+	assert.Len(t, budgets, 2)
+	assert.Equal(t, "/budgets/42/history", (*requests)[0].Path)
+	assert.Equal(t, "per_page=2", (*requests)[0].Query)
+}
+
+func TestClientReorderBudgets(t *testing.T) {
+	c, requests := newTestClient(t, http.StatusNoContent, "")
+
+	err := c.ReorderBudgets(&toshl.BudgetsOrderParams{
+		Order: []string{"2", "1"},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, "/budgets/reorder", (*requests)[0].Path)
+	assert.JSONEq(t, `{"order": ["2", "1"]}`, (*requests)[0].Body)
+}
@@ -0,0 +1,695 @@
+package toshl
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// Interaction is a single HTTPClient call stored in a JSONL cassette. The
+// response headers the HTTPClient exposes are kept in their parsed form:
+// the Link header as Next and the Location header as the Post Response.
+// Status and Header hold the status and headers of the last response
+// received by a RestHTTPClient, e.g. Retry-After or X-RateLimit-*.
+// Downloaded and uploaded files are kept as Data, base64 encoded, the name
+// of uploaded files as File.
+type Interaction struct {
+	Call      string         `json:"call"`
+	Path      string         `json:"path"`
+	Query     string         `json:"query,omitempty"`
+	Body      string         `json:"body,omitempty"`
+	Response  string         `json:"response,omitempty"`
+	Responses []string       `json:"responses,omitempty"`
+	Next      string         `json:"next,omitempty"`
+	Status    int            `json:"status,omitempty"`
+	Header    http.Header    `json:"header,omitempty"`
+	File      string         `json:"file,omitempty"`
+	Data      []byte         `json:"data,omitempty"`
+	Error     *RecordedError `json:"error,omitempty"`
+}
+
+// RecordedError is an error returned by a recorded call
+type RecordedError struct {
+	Message     string       `json:"message"`
+	StatusCode  int          `json:"status_code,omitempty"`
+	Method      string       `json:"method,omitempty"`
+	Path        string       `json:"path,omitempty"`
+	ErrorID     string       `json:"error_id,omitempty"`
+	Description string       `json:"description,omitempty"`
+	Fields      []FieldError `json:"fields,omitempty"`
+}
+
+// The calls of the HTTPClient interface
+const (
+	CallGet         = "Get"
+	CallGetMultiple = "GetMultiple"
+	CallGetPage     = "GetPage"
+	CallPost        = "Post"
+	CallUpdate      = "Update"
+	CallDelete      = "Delete"
+	CallDownload    = "Download"
+	CallUpload      = "Upload"
+)
+
+func newRecordedError(err error) *RecordedError {
+	if err == nil {
+		return nil
+	}
+
+	recorded := &RecordedError{Message: err.Error()}
+
+	if apiErr, ok := asAPIError(err); ok {
+		recorded.StatusCode = apiErr.StatusCode
+		recorded.Method = apiErr.Method
+		recorded.Path = apiErr.Path
+		recorded.ErrorID = apiErr.ErrorID
+		recorded.Description = apiErr.Description
+		recorded.Fields = apiErr.Fields
+	}
+
+	return recorded
+}
+
+// err rebuilds the recorded error of the interaction
+func (i *Interaction) err() error {
+	return i.Error.err(i.Header)
+}
+
+// err rebuilds the recorded error, header being the headers of the
+// response that caused it
+func (e *RecordedError) err(header http.Header) error {
+	if e == nil {
+		return nil
+	}
+
+	if e.StatusCode != 0 {
+		return &APIError{
+			StatusCode:  e.StatusCode,
+			Method:      e.Method,
+			Path:        e.Path,
+			ErrorID:     e.ErrorID,
+			Description: e.Description,
+			Fields:      e.Fields,
+			RetryAfter:  parseRetryAfter(header.Get("Retry-After")),
+		}
+	}
+
+	return errors.New(e.Message)
+}
+
+// Recorder is an HTTPClient decorator writing every call made through it
+// to a JSONL cassette, one Interaction per line. It is safe for concurrent
+// use.
+type Recorder struct {
+	client HTTPClient
+
+	// Scrub lists secrets replaced by "[SCRUBBED]" in the cassette
+	Scrub []string
+
+	mu      sync.Mutex
+	encoder *json.Encoder
+	err     error
+}
+
+// NewRecorder returns a Recorder forwarding calls to client and writing
+// them to w. The token of a RestHTTPClient is scrubbed from the cassette.
+func NewRecorder(client HTTPClient, w io.Writer) *Recorder {
+	r := &Recorder{client: client, encoder: json.NewEncoder(w)}
+
+	if rest, ok := client.(*RestHTTPClient); ok && rest.Token != "" {
+		r.Scrub = append(r.Scrub, rest.Token)
+	}
+
+	return r
+}
+
+// Err returns the first error encountered writing the cassette
+func (r *Recorder) Err() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.err
+}
+
+func (r *Recorder) scrub(s string) string {
+	for _, secret := range r.Scrub {
+		if secret != "" {
+			s = strings.ReplaceAll(s, secret, "[SCRUBBED]")
+		}
+	}
+
+	return s
+}
+
+// observe returns ctx carrying an observer of the responses received by a
+// RestHTTPClient, and a function adding the status and headers of the last
+// one to an Interaction
+func observe(
+	ctx context.Context,
+) (context.Context, func(Interaction) Interaction) {
+	var mu sync.Mutex
+	var last *http.Response
+
+	ctx = context.WithValue(ctx, responseObserverKey{},
+		func(resp *http.Response) {
+			mu.Lock()
+			defer mu.Unlock()
+
+			last = resp
+		})
+
+	return ctx, func(interaction Interaction) Interaction {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if last != nil {
+			interaction.Status = last.StatusCode
+			interaction.Header = last.Header.Clone()
+		}
+
+		return interaction
+	}
+}
+
+func (r *Recorder) record(interaction Interaction, err error) {
+	interaction.Path = r.scrub(interaction.Path)
+	interaction.Query = r.scrub(interaction.Query)
+	interaction.Body = r.scrub(interaction.Body)
+	interaction.Response = r.scrub(interaction.Response)
+	interaction.Next = r.scrub(interaction.Next)
+	interaction.File = r.scrub(interaction.File)
+
+	for _, values := range interaction.Header {
+		for i, value := range values {
+			values[i] = r.scrub(value)
+		}
+	}
+
+	for i, response := range interaction.Responses {
+		interaction.Responses[i] = r.scrub(response)
+	}
+
+	if interaction.Error = newRecordedError(err); interaction.Error != nil {
+		interaction.Error.Message = r.scrub(interaction.Error.Message)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if encodeErr := r.encoder.Encode(interaction); encodeErr != nil &&
+		r.err == nil {
+		r.err = encodeErr
+	}
+}
+
+// Get records a Get call
+func (r *Recorder) Get(APIUrl, queryString string) (string, error) {
+	return r.GetContext(context.Background(), APIUrl, queryString)
+}
+
+// GetContext records a GetContext call
+func (r *Recorder) GetContext(
+	ctx context.Context, APIUrl, queryString string,
+) (string, error) {
+	ctx, withResponse := observe(ctx)
+
+	res, err := getContext(ctx, r.client, APIUrl, queryString)
+	r.record(withResponse(Interaction{
+		Call: CallGet, Path: APIUrl, Query: queryString, Response: res,
+	}), err)
+
+	return res, err
+}
+
+// GetMultiple records a GetMultiple call
+func (r *Recorder) GetMultiple(APIUrl, queryString string) ([]string, error) {
+	return r.GetMultipleContext(context.Background(), APIUrl, queryString)
+}
+
+// GetMultipleContext records a GetMultipleContext call
+func (r *Recorder) GetMultipleContext(
+	ctx context.Context, APIUrl, queryString string,
+) ([]string, error) {
+	ctx, withResponse := observe(ctx)
+
+	res, err := getMultipleContext(ctx, r.client, APIUrl, queryString)
+	r.record(withResponse(Interaction{
+		Call:      CallGetMultiple,
+		Path:      APIUrl,
+		Query:     queryString,
+		Responses: append([]string(nil), res...),
+	}), err)
+
+	return res, err
+}
+
+// GetPage records a GetPage call
+func (r *Recorder) GetPage(
+	APIUrl, queryString string,
+) (string, string, error) {
+	return r.GetPageContext(context.Background(), APIUrl, queryString)
+}
+
+// GetPageContext records a GetPageContext call
+func (r *Recorder) GetPageContext(
+	ctx context.Context, APIUrl, queryString string,
+) (string, string, error) {
+	ctx, withResponse := observe(ctx)
+
+	res, next, err := getPage(ctx, r.client, APIUrl, queryString)
+	r.record(withResponse(Interaction{
+		Call:     CallGetPage,
+		Path:     APIUrl,
+		Query:    queryString,
+		Response: res,
+		Next:     next,
+	}), err)
+
+	return res, next, err
+}
+
+// Post records a Post call
+func (r *Recorder) Post(APIUrl, JSONPayload string) (string, error) {
+	return r.PostContext(context.Background(), APIUrl, JSONPayload)
+}
+
+// PostContext records a PostContext call
+func (r *Recorder) PostContext(
+	ctx context.Context, APIUrl, JSONPayload string,
+) (string, error) {
+	ctx, withResponse := observe(ctx)
+
+	res, err := postContext(ctx, r.client, APIUrl, JSONPayload)
+	r.record(withResponse(Interaction{
+		Call: CallPost, Path: APIUrl, Body: JSONPayload, Response: res,
+	}), err)
+
+	return res, err
+}
+
+// Update records an Update call
+func (r *Recorder) Update(APIUrl, JSONPayload string) (string, error) {
+	return r.UpdateContext(context.Background(), APIUrl, JSONPayload)
+}
+
+// UpdateContext records an UpdateContext call
+func (r *Recorder) UpdateContext(
+	ctx context.Context, APIUrl, JSONPayload string,
+) (string, error) {
+	ctx, withResponse := observe(ctx)
+
+	res, err := updateContext(ctx, r.client, APIUrl, JSONPayload)
+	r.record(withResponse(Interaction{
+		Call: CallUpdate, Path: APIUrl, Body: JSONPayload, Response: res,
+	}), err)
+
+	return res, err
+}
+
+// Delete records a Delete call
+func (r *Recorder) Delete(APIUrl string) error {
+	return r.DeleteContext(context.Background(), APIUrl)
+}
+
+// DeleteContext records a DeleteContext call
+func (r *Recorder) DeleteContext(ctx context.Context, APIUrl string) error {
+	ctx, withResponse := observe(ctx)
+
+	err := deleteContext(ctx, r.client, APIUrl)
+	r.record(withResponse(Interaction{Call: CallDelete, Path: APIUrl}), err)
+
+	return err
+}
+
+// Download records a Download call
+func (r *Recorder) Download(APIUrl, queryString string, w io.Writer) error {
+	return r.DownloadContext(context.Background(), APIUrl, queryString, w)
+}
+
+// DownloadContext records a DownloadContext call
+func (r *Recorder) DownloadContext(
+	ctx context.Context, APIUrl, queryString string, w io.Writer,
+) error {
+	ctx, withResponse := observe(ctx)
+
+	var data bytes.Buffer
+
+	err := download(
+		ctx, r.client, APIUrl, queryString, io.MultiWriter(w, &data))
+	r.record(withResponse(Interaction{
+		Call: CallDownload, Path: APIUrl, Query: queryString,
+		Data: data.Bytes(),
+	}), err)
+
+	return err
+}
+
+// Upload records an Upload call
+func (r *Recorder) Upload(
+	APIUrl, fileName string, file io.Reader,
+) (string, error) {
+	return r.UploadContext(context.Background(), APIUrl, fileName, file)
+}
+
+// UploadContext records an UploadContext call
+func (r *Recorder) UploadContext(
+	ctx context.Context, APIUrl, fileName string, file io.Reader,
+) (string, error) {
+	ctx, withResponse := observe(ctx)
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return "", err
+	}
+
+	res, err := upload(
+		ctx, r.client, APIUrl, fileName, bytes.NewReader(data))
+	r.record(withResponse(Interaction{
+		Call: CallUpload, Path: APIUrl, File: fileName, Data: data,
+		Response: res,
+	}), err)
+
+	return res, err
+}
+
+// MatchMode selects how a Replayer matches calls against its cassette
+type MatchMode int
+
+const (
+	// MatchStrict serves the interactions in the recorded order, each
+	// call having to match the next interaction exactly
+	MatchStrict MatchMode = iota
+
+	// MatchLenient serves the first interaction with the same call, path
+	// and query string regardless of order and payload, reusing the last
+	// one once all of them have been served
+	MatchLenient
+)
+
+// ErrNoInteraction is returned by a Replayer when no recorded interaction
+// matches a call
+var ErrNoInteraction = errors.New("toshl: no matching recorded interaction")
+
+// Replayer is an HTTPClient serving the interactions of a cassette
+// written by a Recorder, for offline tests. It is safe for concurrent use.
+type Replayer struct {
+	// RateLimiter, when set, is waited on before serving each call and
+	// observes the recorded status and headers, like for a RestHTTPClient
+	RateLimiter *RateLimiter
+
+	mode MatchMode
+
+	mu           sync.Mutex
+	interactions []Interaction
+	used         []bool
+	next         int
+}
+
+// NewReplayer returns a Replayer serving the cassette read from r
+func NewReplayer(r io.Reader, mode MatchMode) (*Replayer, error) {
+	var interactions []Interaction
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 64*1024*1024)
+
+	for line := 1; scanner.Scan(); line++ {
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+
+		var interaction Interaction
+		if err := json.Unmarshal(scanner.Bytes(), &interaction); err != nil {
+			return nil, fmt.Errorf("cassette line %d: %w", line, err)
+		}
+
+		interactions = append(interactions, interaction)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return &Replayer{
+		mode:         mode,
+		interactions: interactions,
+		used:         make([]bool, len(interactions)),
+	}, nil
+}
+
+// Remaining returns the number of interactions not served yet
+func (r *Replayer) Remaining() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	remaining := 0
+	for _, used := range r.used {
+		if !used {
+			remaining++
+		}
+	}
+
+	return remaining
+}
+
+func (r *Replayer) match(call Interaction) (*Interaction, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.mode == MatchStrict {
+		if r.next >= len(r.interactions) {
+			return nil, fmt.Errorf("%w: %s %s, cassette exhausted",
+				ErrNoInteraction, call.Call, call.Path)
+		}
+
+		interaction := &r.interactions[r.next]
+		if interaction.Call != call.Call || interaction.Path != call.Path ||
+			interaction.Query != call.Query || interaction.Body != call.Body {
+			return nil, fmt.Errorf("%w: %s %s, expected %s %s",
+				ErrNoInteraction, call.Call, call.Path,
+				interaction.Call, interaction.Path)
+		}
+
+		r.used[r.next] = true
+		r.next++
+
+		return interaction, nil
+	}
+
+	last := -1
+	for i, interaction := range r.interactions {
+		if interaction.Call != call.Call || interaction.Path != call.Path ||
+			interaction.Query != call.Query {
+			continue
+		}
+
+		if !r.used[i] {
+			r.used[i] = true
+			return &r.interactions[i], nil
+		}
+
+		last = i
+	}
+
+	if last < 0 {
+		return nil, fmt.Errorf("%w: %s %s",
+			ErrNoInteraction, call.Call, call.Path)
+	}
+
+	return &r.interactions[last], nil
+}
+
+// serve returns the interaction matching call, once the RateLimiter lets
+// it through
+func (r *Replayer) serve(
+	ctx context.Context, call Interaction,
+) (*Interaction, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	if r.RateLimiter != nil {
+		if err := r.RateLimiter.Wait(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	interaction, err := r.match(call)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.RateLimiter != nil {
+		r.RateLimiter.observe(&http.Response{
+			StatusCode: interaction.Status,
+			Header:     interaction.Header,
+		})
+	}
+
+	return interaction, nil
+}
+
+// Get replays a Get call
+func (r *Replayer) Get(APIUrl, queryString string) (string, error) {
+	return r.GetContext(context.Background(), APIUrl, queryString)
+}
+
+// GetContext replays a GetContext call
+func (r *Replayer) GetContext(
+	ctx context.Context, APIUrl, queryString string,
+) (string, error) {
+	interaction, err := r.serve(ctx, Interaction{
+		Call: CallGet, Path: APIUrl, Query: queryString,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return interaction.Response, interaction.err()
+}
+
+// GetMultiple replays a GetMultiple call
+func (r *Replayer) GetMultiple(APIUrl, queryString string) ([]string, error) {
+	return r.GetMultipleContext(context.Background(), APIUrl, queryString)
+}
+
+// GetMultipleContext replays a GetMultipleContext call
+func (r *Replayer) GetMultipleContext(
+	ctx context.Context, APIUrl, queryString string,
+) ([]string, error) {
+	interaction, err := r.serve(ctx, Interaction{
+		Call: CallGetMultiple, Path: APIUrl, Query: queryString,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return interaction.Responses, interaction.err()
+}
+
+// GetPage replays a GetPage call
+func (r *Replayer) GetPage(
+	APIUrl, queryString string,
+) (string, string, error) {
+	return r.GetPageContext(context.Background(), APIUrl, queryString)
+}
+
+// GetPageContext replays a GetPageContext call
+func (r *Replayer) GetPageContext(
+	ctx context.Context, APIUrl, queryString string,
+) (string, string, error) {
+	interaction, err := r.serve(ctx, Interaction{
+		Call: CallGetPage, Path: APIUrl, Query: queryString,
+	})
+	if err != nil {
+		return "", "", err
+	}
+
+	return interaction.Response, interaction.Next, interaction.err()
+}
+
+// Post replays a Post call
+func (r *Replayer) Post(APIUrl, JSONPayload string) (string, error) {
+	return r.PostContext(context.Background(), APIUrl, JSONPayload)
+}
+
+// PostContext replays a PostContext call
+func (r *Replayer) PostContext(
+	ctx context.Context, APIUrl, JSONPayload string,
+) (string, error) {
+	interaction, err := r.serve(ctx, Interaction{
+		Call: CallPost, Path: APIUrl, Body: JSONPayload,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return interaction.Response, interaction.err()
+}
+
+// Update replays an Update call
+func (r *Replayer) Update(APIUrl, JSONPayload string) (string, error) {
+	return r.UpdateContext(context.Background(), APIUrl, JSONPayload)
+}
+
+// UpdateContext replays an UpdateContext call
+func (r *Replayer) UpdateContext(
+	ctx context.Context, APIUrl, JSONPayload string,
+) (string, error) {
+	interaction, err := r.serve(ctx, Interaction{
+		Call: CallUpdate, Path: APIUrl, Body: JSONPayload,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return interaction.Response, interaction.err()
+}
+
+// Delete replays a Delete call
+func (r *Replayer) Delete(APIUrl string) error {
+	return r.DeleteContext(context.Background(), APIUrl)
+}
+
+// DeleteContext replays a DeleteContext call
+func (r *Replayer) DeleteContext(ctx context.Context, APIUrl string) error {
+	interaction, err := r.serve(ctx, Interaction{
+		Call: CallDelete, Path: APIUrl,
+	})
+	if err != nil {
+		return err
+	}
+
+	return interaction.err()
+}
+
+// Download replays a Download call
+func (r *Replayer) Download(APIUrl, queryString string, w io.Writer) error {
+	return r.DownloadContext(context.Background(), APIUrl, queryString, w)
+}
+
+// DownloadContext replays a DownloadContext call
+func (r *Replayer) DownloadContext(
+	ctx context.Context, APIUrl, queryString string, w io.Writer,
+) error {
+	interaction, err := r.serve(ctx, Interaction{
+		Call: CallDownload, Path: APIUrl, Query: queryString,
+	})
+	if err != nil {
+		return err
+	}
+
+	if _, err := w.Write(interaction.Data); err != nil {
+		return err
+	}
+
+	return interaction.err()
+}
+
+// Upload replays an Upload call
+func (r *Replayer) Upload(
+	APIUrl, fileName string, file io.Reader,
+) (string, error) {
+	return r.UploadContext(context.Background(), APIUrl, fileName, file)
+}
+
+// UploadContext replays an UploadContext call, the uploaded file being
+// ignored
+func (r *Replayer) UploadContext(
+	ctx context.Context, APIUrl, fileName string, file io.Reader,
+) (string, error) {
+	interaction, err := r.serve(ctx, Interaction{
+		Call: CallUpload, Path: APIUrl,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return interaction.Response, interaction.err()
+}
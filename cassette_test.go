@@ -0,0 +1,204 @@
+package toshl_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Philanthropists/toshl-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func newCassetteServer(t *testing.T) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			switch {
+			case r.Method == http.MethodPost:
+				w.Header().Set("Location", "/categories/7")
+				w.WriteHeader(http.StatusCreated)
+			case r.URL.Path == "/categories/404":
+				w.WriteHeader(http.StatusNotFound)
+				fmt.Fprint(w, `{"error_id": "error.object.not_found"}`)
+			default:
+				fmt.Fprintf(w, `[{"id": "1", "name": "Food", "echo": "%s"}]`,
+					r.Header.Get("Authorization"))
+			}
+		}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestRecorderAndReplayer(t *testing.T) {
+	server := newCassetteServer(t)
+
+	var cassette bytes.Buffer
+	recorder := toshl.NewRecorder(&toshl.RestHTTPClient{
+		BaseURL: server.URL,
+		Token:   "secret-token",
+		Client:  server.Client(),
+	}, &cassette)
+
+	recorded := toshl.NewClient("", recorder)
+
+	categories, err := recorded.Categories(nil)
+	assert.Nil(t, err)
+
+	category := &toshl.Category{Name: "Travel", Type: "expense"}
+	assert.Nil(t, recorded.CreateCategory(category))
+
+	_, err = recorded.GetCategory("404")
+	assert.True(t, toshl.IsNotFound(err))
+
+	assert.Nil(t, recorder.Err())
+	assert.Equal(t, 3, strings.Count(cassette.String(), "\n"))
+	assert.NotContains(t, cassette.String(), "secret-token")
+
+	replayer, err := toshl.NewReplayer(
+		bytes.NewReader(cassette.Bytes()), toshl.MatchStrict)
+	assert.Nil(t, err)
+
+	replayed := toshl.NewClient("", replayer)
+
+	replayedCategories, err := replayed.Categories(nil)
+	assert.Nil(t, err)
+	assert.Equal(t, categories[0].Name, replayedCategories[0].Name)
+
+	replayedCategory := &toshl.Category{Name: "Travel", Type: "expense"}
+	assert.Nil(t, replayed.CreateCategory(replayedCategory))
+	assert.Equal(t, category.ID, replayedCategory.ID)
+
+	_, err = replayed.GetCategory("404")
+	assert.True(t, toshl.IsNotFound(err))
+
+	assert.Equal(t, 0, replayer.Remaining())
+
+	_, err = replayed.Categories(nil)
+	assert.ErrorIs(t, err, toshl.ErrNoInteraction)
+}
+
+func TestReplayerStrictMismatch(t *testing.T) {
+	replayer, err := toshl.NewReplayer(strings.NewReader(
+		`{"call": "Get", "path": "accounts", "response": "[]"}`+"\n"),
+		toshl.MatchStrict)
+	assert.Nil(t, err)
+
+	_, err = replayer.Get("budgets", "")
+	assert.ErrorIs(t, err, toshl.ErrNoInteraction)
+}
+
+func TestReplayerLenient(t *testing.T) {
+	replayer, err := toshl.NewReplayer(strings.NewReader(
+		`{"call": "Get", "path": "accounts", "response": "[1]"}`+"\n"+
+			`{"call": "Get", "path": "budgets", "response": "[2]"}`+"\n"+
+			`{"call": "Post", "path": "accounts", "response": "42"}`+"\n"),
+		toshl.MatchLenient)
+	assert.Nil(t, err)
+
+	res, err := replayer.Get("budgets", "")
+	assert.Nil(t, err)
+	assert.Equal(t, "[2]", res)
+
+	id, err := replayer.Post("accounts", `{"name": "different payload"}`)
+	assert.Nil(t, err)
+	assert.Equal(t, "42", id)
+
+	for i := 0; i < 2; i++ {
+		res, err = replayer.Get("accounts", "")
+		assert.Nil(t, err)
+		assert.Equal(t, "[1]", res)
+	}
+
+	assert.Equal(t, 0, replayer.Remaining())
+}
+
+func TestRecorderUpload(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			file, header, err := r.FormFile("file")
+			assert.Nil(t, err)
+			defer file.Close()
+
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprintf(w, `{"id": "5", "filename": %q}`, header.Filename)
+		}))
+	defer server.Close()
+
+	var cassette bytes.Buffer
+	recorder := toshl.NewRecorder(&toshl.RestHTTPClient{
+		BaseURL: server.URL,
+		Client:  server.Client(),
+	}, &cassette)
+
+	image, err := toshl.NewClient("", recorder).UploadImage(
+		"receipt.jpg", strings.NewReader("jpeg data"))
+	assert.Nil(t, err)
+	assert.Equal(t, "5", image.Id)
+	assert.Contains(t, cassette.String(), `"file":"receipt.jpg"`)
+
+	replayer, err := toshl.NewReplayer(&cassette, toshl.MatchStrict)
+	assert.Nil(t, err)
+
+	replayed, err := toshl.NewClient("", replayer).UploadImage(
+		"receipt.jpg", strings.NewReader("jpeg data"))
+	assert.Nil(t, err)
+	assert.Equal(t, image, replayed)
+}
+
+func TestReplayerRateLimit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Retry-After", "60")
+			w.WriteHeader(http.StatusTooManyRequests)
+			fmt.Fprint(w, `{"error_id": "error.rate_limit"}`)
+		}))
+	defer server.Close()
+
+	var cassette bytes.Buffer
+	recorder := toshl.NewRecorder(&toshl.RestHTTPClient{
+		BaseURL: server.URL,
+		Client:  server.Client(),
+	}, &cassette)
+
+	_, err := toshl.NewClient("", recorder).Categories(nil)
+	assert.True(t, toshl.IsRateLimited(err))
+	assert.Contains(t, cassette.String(), `"status":429`)
+	assert.Contains(t, cassette.String(), `"Retry-After":["60"]`)
+
+	replayer, err := toshl.NewReplayer(
+		bytes.NewReader(cassette.Bytes()), toshl.MatchLenient)
+	assert.Nil(t, err)
+
+	replayer.RateLimiter = toshl.NewRateLimiter(
+		toshl.RateLimiterConfig{FailFast: true})
+	replayed := toshl.NewClient("", replayer)
+
+	_, err = replayed.Categories(nil)
+	var apiErr *toshl.APIError
+	if assert.True(t, errors.As(err, &apiErr)) {
+		assert.Equal(t, 60*time.Second, apiErr.RetryAfter)
+	}
+
+	_, err = replayed.Categories(nil)
+	assert.ErrorIs(t, err, toshl.ErrRateLimited)
+}
+
+func TestReplayerCanceled(t *testing.T) {
+	replayer, err := toshl.NewReplayer(strings.NewReader(
+		`{"call": "Get", "path": "accounts", "response": "[]"}`+"\n"),
+		toshl.MatchStrict)
+	assert.Nil(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err = replayer.GetContext(ctx, "accounts", "")
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 1, replayer.Remaining())
+}
@@ -1,6 +1,7 @@
 package toshl
 
 import (
+	"encoding/json"
 	"net/url"
 	"strconv"
 	"time"
@@ -10,10 +11,51 @@ import (
 type Category struct {
 	ID       string         `json:"id"`
 	Name     string         `json:"name"`
-	Modified time.Time      `json:"-"` // omitted
+	Modified time.Time      `json:"-"` // parsed from modified
 	Type     string         `json:"type"`
 	Deleted  bool           `json:"deleted"`
 	Counts   CategoryCounts `json:"counts"`
+
+	// modified is the modified value as returned by the API, sent back
+	// as is on update for conflict detection
+	modified string
+}
+
+// category has the fields of Category without its JSON methods
+type category Category
+
+// UnmarshalJSON decodes a Category, parsing its modified timestamp
+func (c *Category) UnmarshalJSON(data []byte) error {
+	aux := struct {
+		*category
+		Modified string `json:"modified"`
+	}{category: (*category)(c)}
+
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	c.modified = aux.Modified
+	c.Modified, _ = parseModified(aux.Modified)
+
+	return nil
+}
+
+// MarshalJSON encodes a Category, with the modified value received from
+// the API unless Modified was changed since
+func (c Category) MarshalJSON() ([]byte, error) {
+	modified := c.modified
+	if t, err := parseModified(modified); err != nil || !t.Equal(c.Modified) {
+		modified = ""
+		if !c.Modified.IsZero() {
+			modified = c.Modified.UTC().Format(time.RFC3339Nano)
+		}
+	}
+
+	return json.Marshal(struct {
+		category
+		Modified string `json:"modified,omitempty"`
+	}{category(c), modified})
 }
 
 // CategoryQueryParams represents a struct of parameters usable
@@ -39,7 +81,7 @@ func (c *CategoryQueryParams) getQueryString() string {
 	}
 
 	if !c.Since.IsZero() {
-		v.Set("since", c.Since.Format("2006-01-02 15:04:05.000"))
+		v.Set("since", c.Since.Format(sinceFormat))
 	}
 
 	if c.Type != "" {
@@ -0,0 +1,62 @@
+package toshl_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/Philanthropists/toshl-go"
+	"github.com/Philanthropists/toshl-go/toshltest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdateCategoryFuncRetriesOnConflict(t *testing.T) {
+	server := toshltest.NewServer()
+	defer server.Close()
+
+	client := server.NewClient()
+
+	category := &toshl.Category{Name: "Food", Type: "expense"}
+	require.NoError(t, client.CreateCategory(category))
+
+	calls := 0
+	updated, err := client.UpdateCategoryFunc(category.ID,
+		func(c *toshl.Category) error {
+			calls++
+
+			if calls == 1 {
+				other, err := client.GetCategory(category.ID)
+				require.NoError(t, err)
+				require.NoError(t, client.UpdateCategory(other))
+			}
+
+			c.Name = "Groceries"
+			return nil
+		})
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, calls)
+	assert.Equal(t, "Groceries", updated.Name)
+}
+
+func TestCategoryModified(t *testing.T) {
+	var category toshl.Category
+	require.NoError(t, json.Unmarshal(
+		[]byte(`{"id": "1", "modified": "2024-03-05 10:20:30.123"}`),
+		&category))
+
+	assert.Equal(t,
+		time.Date(2024, time.March, 5, 10, 20, 30, 123000000, time.UTC),
+		category.Modified)
+
+	// The value received is sent back as is
+	bs, err := json.Marshal(category)
+	require.NoError(t, err)
+	assert.Contains(t, string(bs), `"modified":"2024-03-05 10:20:30.123"`)
+
+	category.Modified = category.Modified.Add(time.Second)
+	bs, err = json.Marshal(category)
+	require.NoError(t, err)
+	assert.Contains(t, string(bs), `"modified":"2024-03-05T10:20:31.123Z"`)
+}
@@ -1,13 +1,17 @@
 package toshl
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"log"
+	"io"
+	"log/slog"
 	"net/http"
 	"net/url"
 	"runtime"
 	"strconv"
+	"time"
 )
 
 // DefaultBaseURL is ...
@@ -16,24 +20,39 @@ const (
 	ClientVersion  = "0.1"
 )
 
+// maxConflictRetries bounds the retries of the read-modify-write helpers
+const maxConflictRetries = 3
+
 // Client handles API requests
 type Client struct {
 	client HTTPClient
+	logger *slog.Logger
 }
 
-// NewClient returns a new Toshl client
-func NewClient(token string, httpClient HTTPClient) *Client {
+// NewClient returns a new Toshl client. Options only configure the
+// default RestHTTPClient, a custom httpClient is used as given.
+func NewClient(
+	token string, httpClient HTTPClient, opts ...ClientOption,
+) *Client {
 	baseURL, _ := url.Parse(DefaultBaseURL)
 
+	var options clientOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+
 	if httpClient == nil {
 		httpClient = &RestHTTPClient{
-			Client:  &http.Client{},
-			BaseURL: baseURL.String(),
-			Token:   token,
+			Client:      &http.Client{},
+			BaseURL:     baseURL.String(),
+			Token:       token,
+			Logger:      options.logger,
+			RetryPolicy: options.retryPolicy,
+			RateLimiter: options.rateLimiter,
 		}
 	}
 
-	c := &Client{client: httpClient}
+	c := &Client{client: httpClient, logger: options.logger}
 	return c
 }
 
@@ -42,6 +61,27 @@ func (c *Client) GetHTTPClient() HTTPClient {
 	return c.client
 }
 
+// stringValue returns the value pointed to by s, or "" when s is nil
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+
+	return *s
+}
+
+// decode unmarshals an API response, logging the redacted payload when
+// it cannot be decoded
+func (c *Client) decode(ctx context.Context, payload string, v any) error {
+	err := json.Unmarshal([]byte(payload), v)
+	if err != nil && c.logger != nil {
+		c.logger.DebugContext(ctx, "toshl: cannot decode response",
+			"error", err, "body", redactedBody(payload))
+	}
+
+	return err
+}
+
 // GetUserAgentString returns the string for UserAgent
 func GetUserAgentString() string {
 	return fmt.Sprintf(
@@ -50,23 +90,28 @@ func GetUserAgentString() string {
 
 // Accounts returns the list of Accounts
 func (c *Client) Accounts(params *AccountQueryParams) ([]Account, error) {
+	return c.AccountsContext(context.Background(), params)
+}
+
+// AccountsContext is like Accounts but uses ctx for the request
+func (c *Client) AccountsContext(
+	ctx context.Context, params *AccountQueryParams,
+) ([]Account, error) {
 	queryString := ""
 
 	if params != nil {
 		queryString = params.getQueryString()
 	}
 
-	res, err := c.client.Get("accounts", queryString)
+	res, err := getContext(ctx, c.client, "accounts", queryString)
 	if err != nil {
-		log.Println("GET /accounts/: ", err)
 		return nil, err
 	}
 
 	var accounts []Account
 
-	err = json.Unmarshal([]byte(res), &accounts)
+	err = c.decode(ctx, res, &accounts)
 	if err != nil {
-		log.Println("JSON: ", res)
 		return nil, err
 	}
 
@@ -75,17 +120,23 @@ func (c *Client) Accounts(params *AccountQueryParams) ([]Account, error) {
 
 // GetAccount returns the a specific Account
 func (c *Client) GetAccount(accountID string) (*Account, error) {
-	res, err := c.client.Get(fmt.Sprintf("accounts/%s", accountID), "")
+	return c.GetAccountContext(context.Background(), accountID)
+}
+
+// GetAccountContext is like GetAccount but uses ctx for the request
+func (c *Client) GetAccountContext(
+	ctx context.Context, accountID string,
+) (*Account, error) {
+	res, err := getContext(
+		ctx, c.client, fmt.Sprintf("accounts/%s", accountID), "")
 	if err != nil {
-		log.Println(fmt.Sprintf("GET /accounts/%s: ", accountID), err)
 		return nil, err
 	}
 
 	var account *Account
 
-	err = json.Unmarshal([]byte(res), &account)
+	err = c.decode(ctx, res, &account)
 	if err != nil {
-		log.Println("JSON: ", res)
 		return nil, err
 	}
 
@@ -99,17 +150,22 @@ type CreateAccountParams struct {
 
 // CreateAccount creates a Toshl Account
 func (c *Client) CreateAccount(account CreateAccountParams) (string, error) {
+	return c.CreateAccountContext(context.Background(), account)
+}
+
+// CreateAccountContext is like CreateAccount but uses ctx for the request
+func (c *Client) CreateAccountContext(
+	ctx context.Context, account CreateAccountParams,
+) (string, error) {
 	jsonBytes, err := json.Marshal(account)
 	if err != nil {
-		log.Println("CreateAccount: ", err)
 		return "", err
 	}
 
 	jsonStr := string(jsonBytes)
 
-	id, err := c.client.Post("accounts", jsonStr)
+	id, err := postContext(ctx, c.client, "accounts", jsonStr)
 	if err != nil {
-		log.Println("POST /accounts/ ", err)
 		return "", err
 	}
 
@@ -118,9 +174,15 @@ func (c *Client) CreateAccount(account CreateAccountParams) (string, error) {
 
 // SearchAccount search for Account name and return an Account
 func (c *Client) SearchAccount(accountName string) (*Account, error) {
-	accounts, err := c.Accounts(nil)
+	return c.SearchAccountContext(context.Background(), accountName)
+}
+
+// SearchAccountContext is like SearchAccount but uses ctx for the request
+func (c *Client) SearchAccountContext(
+	ctx context.Context, accountName string,
+) (*Account, error) {
+	accounts, err := c.AccountsContext(ctx, nil)
 	if err != nil {
-		log.Println("GET /accounts/: ", err)
 		return nil, err
 	}
 
@@ -135,35 +197,83 @@ func (c *Client) SearchAccount(accountName string) (*Account, error) {
 
 // UpdateAccount updates a Toshl Account
 func (c *Client) UpdateAccount(account *Account) error {
+	return c.UpdateAccountContext(context.Background(), account)
+}
+
+// UpdateAccountContext is like UpdateAccount but uses ctx for the request
+func (c *Client) UpdateAccountContext(
+	ctx context.Context, account *Account,
+) error {
 	jsonBytes, err := json.Marshal(account)
 	if err != nil {
-		log.Println("CreateAccount: ", err)
 		return err
 	}
 
 	jsonStr := string(jsonBytes)
 
-	accountResponse, err := c.client.Update(
-		fmt.Sprintf("accounts/%s", account.ID), jsonStr)
+	accountResponse, err := updateContext(
+		ctx, c.client, fmt.Sprintf("accounts/%s", stringValue(account.ID)),
+		jsonStr)
 	if err != nil {
-		log.Println("PUT /accounts/ ", err)
 		return err
 	}
 
-	err = json.Unmarshal([]byte(accountResponse), account)
+	err = c.decode(ctx, accountResponse, account)
 	if err != nil {
-		log.Println("Cannot decode Account JSON")
 		return err
 	}
 
 	return nil
 }
 
+// UpdateAccountFunc fetches the Account, applies fn to it and updates it,
+// fetching it again and retrying when someone else updated it meanwhile.
+// It returns the updated Account, or the conflict error once
+// maxConflictRetries retries are exhausted.
+func (c *Client) UpdateAccountFunc(
+	accountID string, fn func(*Account) error,
+) (*Account, error) {
+	return c.UpdateAccountFuncContext(context.Background(), accountID, fn)
+}
+
+// UpdateAccountFuncContext is like UpdateAccountFunc but uses ctx for the
+// requests
+func (c *Client) UpdateAccountFuncContext(
+	ctx context.Context, accountID string, fn func(*Account) error,
+) (*Account, error) {
+	for attempt := 0; ; attempt++ {
+		account, err := c.GetAccountContext(ctx, accountID)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := fn(account); err != nil {
+			return nil, err
+		}
+
+		err = c.UpdateAccountContext(ctx, account)
+		if err == nil {
+			return account, nil
+		}
+
+		if !IsConflict(err) || attempt == maxConflictRetries {
+			return nil, err
+		}
+	}
+}
+
 // DeleteAccount deletes a Toshl Account
 func (c *Client) DeleteAccount(account *Account) error {
-	err := c.client.Delete(fmt.Sprintf("accounts/%s", account.ID))
+	return c.DeleteAccountContext(context.Background(), account)
+}
+
+// DeleteAccountContext is like DeleteAccount but uses ctx for the request
+func (c *Client) DeleteAccountContext(
+	ctx context.Context, account *Account,
+) error {
+	err := deleteContext(
+		ctx, c.client, fmt.Sprintf("accounts/%s", stringValue(account.ID)))
 	if err != nil {
-		log.Print("DELETE /accounts/ ", err)
 		return err
 	}
 
@@ -172,11 +282,19 @@ func (c *Client) DeleteAccount(account *Account) error {
 
 // MoveAccount move a Toshl Account to a different position
 func (c *Client) MoveAccount(account *Account, position int) error {
+	return c.MoveAccountContext(context.Background(), account, position)
+}
+
+// MoveAccountContext is like MoveAccount but uses ctx for the request
+func (c *Client) MoveAccountContext(
+	ctx context.Context, account *Account, position int,
+) error {
 	jsonStr := fmt.Sprintf(`{"position": %s}`, strconv.Itoa(position))
 
-	_, err := c.client.Post(fmt.Sprintf("accounts/%s", account.ID), jsonStr)
+	_, err := postContext(
+		ctx, c.client, fmt.Sprintf("accounts/%s", stringValue(account.ID)),
+		jsonStr)
 	if err != nil {
-		log.Print("POST /accounts/ ", err)
 		return err
 	}
 
@@ -185,17 +303,22 @@ func (c *Client) MoveAccount(account *Account, position int) error {
 
 // ReorderAccounts change the order of Toshl accounts
 func (c *Client) ReorderAccounts(order *AccountsOrderParams) error {
+	return c.ReorderAccountsContext(context.Background(), order)
+}
+
+// ReorderAccountsContext is like ReorderAccounts but uses ctx for the request
+func (c *Client) ReorderAccountsContext(
+	ctx context.Context, order *AccountsOrderParams,
+) error {
 	jsonBytes, err := json.Marshal(order)
 	if err != nil {
-		log.Println("ReorderAccounts: ", err)
 		return err
 	}
 
 	jsonStr := string(jsonBytes)
 
-	_, err = c.client.Post("accounts/reorder", jsonStr)
+	_, err = postContext(ctx, c.client, "accounts/reorder", jsonStr)
 	if err != nil {
-		log.Print("POST /accounts/reorder ", err)
 		return err
 	}
 
@@ -204,17 +327,22 @@ func (c *Client) ReorderAccounts(order *AccountsOrderParams) error {
 
 // MergeAccounts merges two ore more Toshl accounts into a single one
 func (c *Client) MergeAccounts(order *AccountsMergeParams) error {
+	return c.MergeAccountsContext(context.Background(), order)
+}
+
+// MergeAccountsContext is like MergeAccounts but uses ctx for the request
+func (c *Client) MergeAccountsContext(
+	ctx context.Context, order *AccountsMergeParams,
+) error {
 	jsonBytes, err := json.Marshal(order)
 	if err != nil {
-		log.Println("MergeAccounts: ", err)
 		return err
 	}
 
 	jsonStr := string(jsonBytes)
 
-	_, err = c.client.Post("accounts/merge", jsonStr)
+	_, err = postContext(ctx, c.client, "accounts/merge", jsonStr)
 	if err != nil {
-		log.Print("POST /accounts/merge ", err)
 		return err
 	}
 
@@ -223,23 +351,28 @@ func (c *Client) MergeAccounts(order *AccountsMergeParams) error {
 
 // Budgets returns the list of Budgets
 func (c *Client) Budgets(params *BudgetQueryParams) ([]Budget, error) {
+	return c.BudgetsContext(context.Background(), params)
+}
+
+// BudgetsContext is like Budgets but uses ctx for the request
+func (c *Client) BudgetsContext(
+	ctx context.Context, params *BudgetQueryParams,
+) ([]Budget, error) {
 	queryString := ""
 
 	if params != nil {
 		queryString = params.getQueryString()
 	}
 
-	res, err := c.client.Get("budgets", queryString)
+	res, err := getContext(ctx, c.client, "budgets", queryString)
 	if err != nil {
-		log.Print("GET /budgets/: ", err)
 		return nil, err
 	}
 
 	var budgets []Budget
 
-	err = json.Unmarshal([]byte(res), &budgets)
+	err = c.decode(ctx, res, &budgets)
 	if err != nil {
-		log.Println("JSON: ", res)
 		return nil, err
 	}
 
@@ -248,42 +381,200 @@ func (c *Client) Budgets(params *BudgetQueryParams) ([]Budget, error) {
 
 // GetBudget returns the a specific Budget
 func (c *Client) GetBudget(budgetID string) (*Budget, error) {
-	res, err := c.client.Get(fmt.Sprintf("budgets/%s", budgetID), "")
+	return c.GetBudgetContext(context.Background(), budgetID)
+}
+
+// GetBudgetContext is like GetBudget but uses ctx for the request
+func (c *Client) GetBudgetContext(
+	ctx context.Context, budgetID string,
+) (*Budget, error) {
+	res, err := getContext(
+		ctx, c.client, fmt.Sprintf("budgets/%s", budgetID), "")
 	if err != nil {
-		log.Print(fmt.Sprintf("GET /budgets/%s: ", budgetID), err)
 		return nil, err
 	}
 
 	var budget *Budget
 
-	err = json.Unmarshal([]byte(res), &budget)
+	err = c.decode(ctx, res, &budget)
 	if err != nil {
-		log.Println("JSON: ", res)
 		return nil, err
 	}
 
 	return budget, nil
 }
 
+// CreateBudget creates a Toshl Budget
+func (c *Client) CreateBudget(budget *Budget) error {
+	return c.CreateBudgetContext(context.Background(), budget)
+}
+
+// CreateBudgetContext is like CreateBudget but uses ctx for the request
+func (c *Client) CreateBudgetContext(
+	ctx context.Context, budget *Budget,
+) error {
+	jsonBytes, err := json.Marshal(budget)
+	if err != nil {
+		return err
+	}
+
+	jsonStr := string(jsonBytes)
+
+	id, err := postContext(ctx, c.client, "budgets", jsonStr)
+	if err != nil {
+		return err
+	}
+
+	budget.ID = id
+
+	return nil
+}
+
+// UpdateBudget updates a Toshl Budget. For recurring budgets mode selects
+// whether only this iteration or the series is updated, defaulting to
+// RepeatOne.
+func (c *Client) UpdateBudget(budget *Budget, mode RepeatMode) error {
+	return c.UpdateBudgetContext(context.Background(), budget, mode)
+}
+
+// UpdateBudgetContext is like UpdateBudget but uses ctx for the request
+func (c *Client) UpdateBudgetContext(
+	ctx context.Context, budget *Budget, mode RepeatMode,
+) error {
+	queryString, err := repeatQueryString(
+		"update", mode, budget.isRecurring())
+	if err != nil {
+		return err
+	}
+
+	jsonBytes, err := json.Marshal(budget)
+	if err != nil {
+		return err
+	}
+
+	jsonStr := string(jsonBytes)
+
+	budgetResponse, err := updateContext(ctx, c.client, withQueryString(
+		fmt.Sprintf("budgets/%s", budget.ID), queryString), jsonStr)
+	if err != nil {
+		return err
+	}
+
+	err = c.decode(ctx, budgetResponse, budget)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// DeleteBudget deletes a Toshl Budget. For recurring budgets mode selects
+// whether only this iteration or the series is deleted, defaulting to
+// RepeatOne.
+func (c *Client) DeleteBudget(budget *Budget, mode RepeatMode) error {
+	return c.DeleteBudgetContext(context.Background(), budget, mode)
+}
+
+// DeleteBudgetContext is like DeleteBudget but uses ctx for the request
+func (c *Client) DeleteBudgetContext(
+	ctx context.Context, budget *Budget, mode RepeatMode,
+) error {
+	queryString, err := repeatQueryString(
+		"delete", mode, budget.isRecurring())
+	if err != nil {
+		return err
+	}
+
+	err = deleteContext(ctx, c.client, withQueryString(
+		fmt.Sprintf("budgets/%s", budget.ID), queryString))
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ReorderBudgets change the order of Toshl budgets
+func (c *Client) ReorderBudgets(order *BudgetsOrderParams) error {
+	return c.ReorderBudgetsContext(context.Background(), order)
+}
+
+// ReorderBudgetsContext is like ReorderBudgets but uses ctx for the request
+func (c *Client) ReorderBudgetsContext(
+	ctx context.Context, order *BudgetsOrderParams,
+) error {
+	jsonBytes, err := json.Marshal(order)
+	if err != nil {
+		return err
+	}
+
+	jsonStr := string(jsonBytes)
+
+	_, err = postContext(ctx, c.client, "budgets/reorder", jsonStr)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// BudgetHistory returns the past iterations of a recurring Budget
+func (c *Client) BudgetHistory(
+	budgetID string, params *BudgetHistoryParams,
+) ([]Budget, error) {
+	return c.BudgetHistoryContext(context.Background(), budgetID, params)
+}
+
+// BudgetHistoryContext is like BudgetHistory but uses ctx for the request
+func (c *Client) BudgetHistoryContext(
+	ctx context.Context, budgetID string, params *BudgetHistoryParams,
+) ([]Budget, error) {
+	queryString := ""
+
+	if params != nil {
+		queryString = params.getQueryString()
+	}
+
+	res, err := getContext(
+		ctx, c.client, fmt.Sprintf("budgets/%s/history", budgetID), queryString)
+	if err != nil {
+		return nil, err
+	}
+
+	var budgets []Budget
+
+	err = c.decode(ctx, res, &budgets)
+	if err != nil {
+		return nil, err
+	}
+
+	return budgets, nil
+}
+
 // Categories returns the list of Categories
 func (c *Client) Categories(params *CategoryQueryParams) ([]Category, error) {
+	return c.CategoriesContext(context.Background(), params)
+}
+
+// CategoriesContext is like Categories but uses ctx for the request
+func (c *Client) CategoriesContext(
+	ctx context.Context, params *CategoryQueryParams,
+) ([]Category, error) {
 	queryString := ""
 
 	if params != nil {
 		queryString = params.getQueryString()
 	}
 
-	res, err := c.client.Get("categories", queryString)
+	res, err := getContext(ctx, c.client, "categories", queryString)
 	if err != nil {
-		log.Print("GET /categories/: ", err)
 		return nil, err
 	}
 
 	var categories []Category
 
-	err = json.Unmarshal([]byte(res), &categories)
+	err = c.decode(ctx, res, &categories)
 	if err != nil {
-		log.Println("JSON: ", res)
 		return nil, err
 	}
 
@@ -292,17 +583,23 @@ func (c *Client) Categories(params *CategoryQueryParams) ([]Category, error) {
 
 // GetCategory returns the a specific Category
 func (c *Client) GetCategory(categoryID string) (*Category, error) {
-	res, err := c.client.Get(fmt.Sprintf("categories/%s", categoryID), "")
+	return c.GetCategoryContext(context.Background(), categoryID)
+}
+
+// GetCategoryContext is like GetCategory but uses ctx for the request
+func (c *Client) GetCategoryContext(
+	ctx context.Context, categoryID string,
+) (*Category, error) {
+	res, err := getContext(
+		ctx, c.client, fmt.Sprintf("categories/%s", categoryID), "")
 	if err != nil {
-		log.Print(fmt.Sprintf("GET /categories/%s: ", categoryID), err)
 		return nil, err
 	}
 
 	var category *Category
 
-	err = json.Unmarshal([]byte(res), &category)
+	err = c.decode(ctx, res, &category)
 	if err != nil {
-		log.Println("JSON: ", res)
 		return nil, err
 	}
 
@@ -311,17 +608,22 @@ func (c *Client) GetCategory(categoryID string) (*Category, error) {
 
 // CreateCategory creates a Toshl Category
 func (c *Client) CreateCategory(category *Category) error {
+	return c.CreateCategoryContext(context.Background(), category)
+}
+
+// CreateCategoryContext is like CreateCategory but uses ctx for the request
+func (c *Client) CreateCategoryContext(
+	ctx context.Context, category *Category,
+) error {
 	jsonBytes, err := json.Marshal(category)
 	if err != nil {
-		log.Println("CeateCategory: ", err)
 		return err
 	}
 
 	jsonStr := string(jsonBytes)
 
-	id, err := c.client.Post("categories", jsonStr)
+	id, err := postContext(ctx, c.client, "categories", jsonStr)
 	if err != nil {
-		log.Print("POST /categories/ ", err)
 		return err
 	}
 
@@ -332,35 +634,80 @@ func (c *Client) CreateCategory(category *Category) error {
 
 // UpdateCategory updates a Toshl Category
 func (c *Client) UpdateCategory(category *Category) error {
+	return c.UpdateCategoryContext(context.Background(), category)
+}
+
+// UpdateCategoryContext is like UpdateCategory but uses ctx for the request
+func (c *Client) UpdateCategoryContext(
+	ctx context.Context, category *Category,
+) error {
 	jsonBytes, err := json.Marshal(category)
 	if err != nil {
-		log.Println("UpdateCategory: ", err)
 		return err
 	}
 
 	jsonStr := string(jsonBytes)
 
-	categoryResponse, err := c.client.Update(
-		fmt.Sprintf("categories/%s", category.ID), jsonStr)
+	categoryResponse, err := updateContext(
+		ctx, c.client, fmt.Sprintf("categories/%s", category.ID), jsonStr)
 	if err != nil {
-		log.Print("PUT /categories/ ", err)
 		return err
 	}
 
-	err = json.Unmarshal([]byte(categoryResponse), category)
+	err = c.decode(ctx, categoryResponse, category)
 	if err != nil {
-		log.Println("Cannot decode Category JSON")
 		return err
 	}
 
 	return nil
 }
 
+// UpdateCategoryFunc fetches the Category, applies fn to it and updates
+// it, retrying on conflicts like UpdateAccountFunc
+func (c *Client) UpdateCategoryFunc(
+	categoryID string, fn func(*Category) error,
+) (*Category, error) {
+	return c.UpdateCategoryFuncContext(context.Background(), categoryID, fn)
+}
+
+// UpdateCategoryFuncContext is like UpdateCategoryFunc but uses ctx for
+// the requests
+func (c *Client) UpdateCategoryFuncContext(
+	ctx context.Context, categoryID string, fn func(*Category) error,
+) (*Category, error) {
+	for attempt := 0; ; attempt++ {
+		category, err := c.GetCategoryContext(ctx, categoryID)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := fn(category); err != nil {
+			return nil, err
+		}
+
+		err = c.UpdateCategoryContext(ctx, category)
+		if err == nil {
+			return category, nil
+		}
+
+		if !IsConflict(err) || attempt == maxConflictRetries {
+			return nil, err
+		}
+	}
+}
+
 // DeleteCategory deletes a Toshl Category
 func (c *Client) DeleteCategory(category *Category) error {
-	err := c.client.Delete(fmt.Sprintf("categories/%s", category.ID))
+	return c.DeleteCategoryContext(context.Background(), category)
+}
+
+// DeleteCategoryContext is like DeleteCategory but uses ctx for the request
+func (c *Client) DeleteCategoryContext(
+	ctx context.Context, category *Category,
+) error {
+	err := deleteContext(
+		ctx, c.client, fmt.Sprintf("categories/%s", category.ID))
 	if err != nil {
-		log.Print("DELETE /categories/ ", err)
 		return err
 	}
 
@@ -369,71 +716,852 @@ func (c *Client) DeleteCategory(category *Category) error {
 
 // MergeCategories merges two ore more Toshl categories into a single one
 func (c *Client) MergeCategories(order *CategoriesMergeParams) error {
+	return c.MergeCategoriesContext(context.Background(), order)
+}
+
+// MergeCategoriesContext is like MergeCategories but uses ctx for the request
+func (c *Client) MergeCategoriesContext(
+	ctx context.Context, order *CategoriesMergeParams,
+) error {
 	jsonBytes, err := json.Marshal(order)
 	if err != nil {
-		log.Println("MergeCategories: ", err)
 		return err
 	}
 
 	jsonStr := string(jsonBytes)
 
-	_, err = c.client.Post("categories/merge", jsonStr)
+	_, err = postContext(ctx, c.client, "categories/merge", jsonStr)
 	if err != nil {
-		log.Print("POST /categories/merge ", err)
 		return err
 	}
 
 	return nil
 }
 
-func (c *Client) Entries(params *EntryQueryParams) ([]Entry, error) {
+// Tags returns the list of Tags
+func (c *Client) Tags(params *TagQueryParams) ([]Tag, error) {
+	return c.TagsContext(context.Background(), params)
+}
+
+// TagsContext is like Tags but uses ctx for the request
+func (c *Client) TagsContext(
+	ctx context.Context, params *TagQueryParams,
+) ([]Tag, error) {
 	queryString := ""
-	var err error
 
 	if params != nil {
-		queryString, err = params.getQueryString()
-		if err != nil {
-			log.Fatal(err)
-		}
+		queryString = params.getQueryString()
 	}
 
-	responses, err := c.client.GetMultiple("entries", queryString)
+	res, err := getContext(ctx, c.client, "tags", queryString)
 	if err != nil {
-		log.Println("GET /entries/: ", err)
 		return nil, err
 	}
 
-	var entries []Entry
+	var tags []Tag
 
-	for _, response := range responses {
-		var responseEntries []Entry
-		err = json.Unmarshal([]byte(response), &responseEntries)
-		if err != nil {
-			return nil, err
-		}
+	err = c.decode(ctx, res, &tags)
+	if err != nil {
+		return nil, err
+	}
 
-		entries = append(entries, responseEntries...)
+	return tags, nil
+}
+
+// GetTag returns a specific Tag
+func (c *Client) GetTag(tagID string) (*Tag, error) {
+	return c.GetTagContext(context.Background(), tagID)
+}
+
+// GetTagContext is like GetTag but uses ctx for the request
+func (c *Client) GetTagContext(
+	ctx context.Context, tagID string,
+) (*Tag, error) {
+	res, err := getContext(
+		ctx, c.client, fmt.Sprintf("tags/%s", tagID), "")
+	if err != nil {
+		return nil, err
 	}
 
-	return entries, nil
+	var tag *Tag
+
+	err = c.decode(ctx, res, &tag)
+	if err != nil {
+		return nil, err
+	}
+
+	return tag, nil
 }
 
-func (c *Client) CreateEntry(entry *Entry) error {
-	jsonBytes, err := json.Marshal(entry)
+// CreateTag creates a Toshl Tag
+func (c *Client) CreateTag(tag *Tag) error {
+	return c.CreateTagContext(context.Background(), tag)
+}
+
+// CreateTagContext is like CreateTag but uses ctx for the request
+func (c *Client) CreateTagContext(
+	ctx context.Context, tag *Tag,
+) error {
+	jsonBytes, err := json.Marshal(tag)
 	if err != nil {
-		log.Println("CreateEntry: ", err)
 		return err
 	}
 
 	jsonStr := string(jsonBytes)
 
-	id, err := c.client.Post("entries", jsonStr)
+	id, err := postContext(ctx, c.client, "tags", jsonStr)
 	if err != nil {
-		log.Println("POST /entries/ ", err)
 		return err
 	}
 
-	entry.Id = &id
+	tag.ID = id
+
+	return nil
+}
+
+// UpdateTag updates a Toshl Tag
+func (c *Client) UpdateTag(tag *Tag) error {
+	return c.UpdateTagContext(context.Background(), tag)
+}
+
+// UpdateTagContext is like UpdateTag but uses ctx for the request
+func (c *Client) UpdateTagContext(
+	ctx context.Context, tag *Tag,
+) error {
+	jsonBytes, err := json.Marshal(tag)
+	if err != nil {
+		return err
+	}
+
+	jsonStr := string(jsonBytes)
+
+	tagResponse, err := updateContext(
+		ctx, c.client, fmt.Sprintf("tags/%s", tag.ID), jsonStr)
+	if err != nil {
+		return err
+	}
+
+	err = c.decode(ctx, tagResponse, tag)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// DeleteTag deletes a Toshl Tag
+func (c *Client) DeleteTag(tag *Tag) error {
+	return c.DeleteTagContext(context.Background(), tag)
+}
+
+// DeleteTagContext is like DeleteTag but uses ctx for the request
+func (c *Client) DeleteTagContext(
+	ctx context.Context, tag *Tag,
+) error {
+	err := deleteContext(
+		ctx, c.client, fmt.Sprintf("tags/%s", tag.ID))
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// MergeTags merges two or more Toshl tags into a single one
+func (c *Client) MergeTags(order *TagsMergeParams) error {
+	return c.MergeTagsContext(context.Background(), order)
+}
+
+// MergeTagsContext is like MergeTags but uses ctx for the request
+func (c *Client) MergeTagsContext(
+	ctx context.Context, order *TagsMergeParams,
+) error {
+	jsonBytes, err := json.Marshal(order)
+	if err != nil {
+		return err
+	}
+
+	jsonStr := string(jsonBytes)
+
+	_, err = postContext(ctx, c.client, "tags/merge", jsonStr)
+	if err != nil {
+		return err
+	}
 
 	return nil
 }
+
+// Currencies returns the list of currencies supported by Toshl
+func (c *Client) Currencies(
+	params *CurrencyQueryParams,
+) ([]CurrencyInfo, error) {
+	return c.CurrenciesContext(context.Background(), params)
+}
+
+// CurrenciesContext is like Currencies but uses ctx for the request
+func (c *Client) CurrenciesContext(
+	ctx context.Context, params *CurrencyQueryParams,
+) ([]CurrencyInfo, error) {
+	queryString := ""
+
+	if params != nil {
+		queryString = params.getQueryString()
+	}
+
+	res, err := getContext(ctx, c.client, "currencies", queryString)
+	if err != nil {
+		return nil, err
+	}
+
+	var currencies currencyList
+
+	err = c.decode(ctx, res, &currencies)
+	if err != nil {
+		return nil, err
+	}
+
+	return currencies, nil
+}
+
+// Rates returns the exchange rates of a given date
+func (c *Client) Rates(date Date) (*ExchangeRates, error) {
+	return c.RatesContext(context.Background(), date)
+}
+
+// RatesContext is like Rates but uses ctx for the request
+func (c *Client) RatesContext(
+	ctx context.Context, date Date,
+) (*ExchangeRates, error) {
+	v := url.Values{}
+	v.Set("date", date.String())
+
+	res, err := getContext(ctx, c.client, "currencies/rates", v.Encode())
+	if err != nil {
+		return nil, err
+	}
+
+	var rates *ExchangeRates
+
+	err = c.decode(ctx, res, &rates)
+	if err != nil {
+		return nil, err
+	}
+
+	return rates, nil
+}
+
+// Me returns the profile of the authenticated user
+func (c *Client) Me() (*User, error) {
+	return c.MeContext(context.Background())
+}
+
+// MeContext is like Me but uses ctx for the request
+func (c *Client) MeContext(ctx context.Context) (*User, error) {
+	res, err := getContext(ctx, c.client, "me", "")
+	if err != nil {
+		return nil, err
+	}
+
+	var user *User
+
+	err = c.decode(ctx, res, &user)
+	if err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// UpdateMe updates the settings of the authenticated user
+func (c *Client) UpdateMe(user *User) error {
+	return c.UpdateMeContext(context.Background(), user)
+}
+
+// UpdateMeContext is like UpdateMe but uses ctx for the request
+func (c *Client) UpdateMeContext(ctx context.Context, user *User) error {
+	jsonBytes, err := json.Marshal(user)
+	if err != nil {
+		return err
+	}
+
+	jsonStr := string(jsonBytes)
+
+	userResponse, err := updateContext(ctx, c.client, "me", jsonStr)
+	if err != nil {
+		return err
+	}
+
+	err = c.decode(ctx, userResponse, user)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Entries returns the list of Entries, following every page
+func (c *Client) Entries(params *EntryQueryParams) ([]Entry, error) {
+	return c.EntriesContext(context.Background(), params)
+}
+
+// EntriesContext is like Entries but uses ctx for the request
+func (c *Client) EntriesContext(
+	ctx context.Context, params *EntryQueryParams,
+) ([]Entry, error) {
+	queryString := ""
+	var err error
+
+	if params != nil {
+		queryString, err = params.getQueryString()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	responses, err := getMultipleContext(ctx, c.client, "entries", queryString)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []Entry
+
+	for _, response := range responses {
+		var responseEntries []Entry
+		err = c.decode(ctx, response, &responseEntries)
+		if err != nil {
+			return nil, err
+		}
+
+		entries = append(entries, responseEntries...)
+	}
+
+	return entries, nil
+}
+
+// CreateEntry creates a Toshl Entry, checking its Repeat rule first
+func (c *Client) CreateEntry(entry *Entry) error {
+	return c.CreateEntryContext(context.Background(), entry)
+}
+
+// CreateEntryContext is like CreateEntry but uses ctx for the request
+func (c *Client) CreateEntryContext(ctx context.Context, entry *Entry) error {
+	if entry.Repeat != nil {
+		if err := entry.Repeat.Validate(); err != nil {
+			return err
+		}
+	}
+
+	jsonBytes, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	jsonStr := string(jsonBytes)
+
+	id, err := postContext(ctx, c.client, "entries", jsonStr)
+	if err != nil {
+		return err
+	}
+
+	entry.Id = &id
+
+	return nil
+}
+
+// CreateTransfer creates an Entry transferring amount from one account to
+// the other, whatever the sign of amount
+func (c *Client) CreateTransfer(
+	from, to *Account, amount Amount, opts *TransferOptions,
+) (*Entry, error) {
+	return c.CreateTransferContext(
+		context.Background(), from, to, amount, opts)
+}
+
+// CreateTransferContext is like CreateTransfer but uses ctx for the request
+func (c *Client) CreateTransferContext(
+	ctx context.Context, from, to *Account, amount Amount,
+	opts *TransferOptions,
+) (*Entry, error) {
+	entry, err := transferEntry(from, to, amount, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	err = c.CreateEntryContext(ctx, entry)
+	if err != nil {
+		return nil, err
+	}
+
+	return entry, nil
+}
+
+// CreateSplitEntry splits parent into children, e.g. a receipt into
+// several categories. The amounts of children must sum to the amount of
+// parent. Parent is created first unless it has an ID, then every child,
+// taking the account, currency and date of parent when not set. When a
+// child fails, the children created so far and parent, unless it already
+// had an ID, are deleted on a best effort basis.
+func (c *Client) CreateSplitEntry(parent *Entry, children []Entry) error {
+	return c.CreateSplitEntryContext(context.Background(), parent, children)
+}
+
+// CreateSplitEntryContext is like CreateSplitEntry but uses ctx for the
+// requests
+func (c *Client) CreateSplitEntryContext(
+	ctx context.Context, parent *Entry, children []Entry,
+) error {
+	if err := validateSplit(parent, children); err != nil {
+		return err
+	}
+
+	created := []*Entry{}
+
+	if parent.Id == nil {
+		if err := c.CreateEntryContext(ctx, parent); err != nil {
+			return err
+		}
+
+		created = append(created, parent)
+	}
+
+	split := &Split{}
+
+	for i := range children {
+		child := &children[i]
+
+		if child.Account == "" {
+			child.Account = parent.Account
+		}
+
+		if child.Currency.Code == "" {
+			child.Currency = parent.Currency
+		}
+
+		if child.Date == "" {
+			child.Date = parent.Date
+		}
+
+		child.Split = &Split{Parent: *parent.Id}
+
+		if err := c.CreateEntryContext(ctx, child); err != nil {
+			c.deleteCreated(ctx, created)
+			return err
+		}
+
+		created = append(created, child)
+		split.Children = append(split.Children, *child.Id)
+	}
+
+	parent.Split = split
+
+	return nil
+}
+
+// deleteCreated deletes entries in reverse order of creation, ignoring
+// failures, even when ctx is done
+func (c *Client) deleteCreated(ctx context.Context, entries []*Entry) {
+	ctx = context.WithoutCancel(ctx)
+
+	for i := len(entries) - 1; i >= 0; i-- {
+		err := c.DeleteEntryContext(ctx, entries[i], RepeatOne)
+		if err == nil {
+			entries[i].Id = nil
+		}
+	}
+}
+
+// GetSplit returns the parent and children of the split the Entry belongs
+// to, be it the parent or one of the children
+func (c *Client) GetSplit(entryID string) (*Entry, []Entry, error) {
+	return c.GetSplitContext(context.Background(), entryID)
+}
+
+// GetSplitContext is like GetSplit but uses ctx for the requests
+func (c *Client) GetSplitContext(
+	ctx context.Context, entryID string,
+) (*Entry, []Entry, error) {
+	parent, err := c.GetEntryContext(ctx, entryID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if parent.Split != nil && parent.Split.Parent != "" {
+		parent, err = c.GetEntryContext(ctx, parent.Split.Parent)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if !parent.IsSplit() {
+		return parent, nil, nil
+	}
+
+	children := make([]Entry, 0, len(parent.Split.Children))
+
+	for _, childID := range parent.Split.Children {
+		child, err := c.GetEntryContext(ctx, childID)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		children = append(children, *child)
+	}
+
+	return parent, children, nil
+}
+
+// UnsplitEntry deletes the children of a split parent, which is then
+// refreshed
+func (c *Client) UnsplitEntry(parent *Entry) error {
+	return c.UnsplitEntryContext(context.Background(), parent)
+}
+
+// UnsplitEntryContext is like UnsplitEntry but uses ctx for the requests
+func (c *Client) UnsplitEntryContext(
+	ctx context.Context, parent *Entry,
+) error {
+	if !parent.IsSplit() {
+		return nil
+	}
+
+	for _, childID := range parent.Split.Children {
+		child := &Entry{Id: &childID}
+
+		// Children already deleted are fine
+		err := c.DeleteEntryContext(ctx, child, RepeatOne)
+		if err != nil && !IsNotFound(err) {
+			return err
+		}
+	}
+
+	refreshed, err := c.GetEntryContext(ctx, stringValue(parent.Id))
+	if err != nil {
+		return err
+	}
+
+	*parent = *refreshed
+
+	return nil
+}
+
+// GetEntry returns a specific Entry
+func (c *Client) GetEntry(entryID string) (*Entry, error) {
+	return c.GetEntryContext(context.Background(), entryID)
+}
+
+// GetEntryContext is like GetEntry but uses ctx for the request
+func (c *Client) GetEntryContext(
+	ctx context.Context, entryID string,
+) (*Entry, error) {
+	res, err := getContext(
+		ctx, c.client, fmt.Sprintf("entries/%s", entryID), "")
+	if err != nil {
+		return nil, err
+	}
+
+	var entry *Entry
+
+	err = c.decode(ctx, res, &entry)
+	if err != nil {
+		return nil, err
+	}
+
+	return entry, nil
+}
+
+// UpdateEntry updates a Toshl Entry. For repeating entries mode selects
+// which entries of the series are updated, defaulting to RepeatOne.
+func (c *Client) UpdateEntry(entry *Entry, mode RepeatMode) error {
+	return c.UpdateEntryContext(context.Background(), entry, mode)
+}
+
+// UpdateEntryContext is like UpdateEntry but uses ctx for the request
+func (c *Client) UpdateEntryContext(
+	ctx context.Context, entry *Entry, mode RepeatMode,
+) error {
+	queryString, err := repeatQueryString(
+		"update", mode, entry.Repeat != nil)
+	if err != nil {
+		return err
+	}
+
+	jsonBytes, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	jsonStr := string(jsonBytes)
+
+	entryResponse, err := updateContext(
+		ctx, c.client, entryPath(entry, queryString), jsonStr)
+	if err != nil {
+		return err
+	}
+
+	err = c.decode(ctx, entryResponse, entry)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// DeleteEntry deletes a Toshl Entry. For repeating entries mode selects
+// which entries of the series are deleted, defaulting to RepeatOne.
+func (c *Client) DeleteEntry(entry *Entry, mode RepeatMode) error {
+	return c.DeleteEntryContext(context.Background(), entry, mode)
+}
+
+// DeleteEntryContext is like DeleteEntry but uses ctx for the request
+func (c *Client) DeleteEntryContext(
+	ctx context.Context, entry *Entry, mode RepeatMode,
+) error {
+	queryString, err := repeatQueryString(
+		"delete", mode, entry.Repeat != nil)
+	if err != nil {
+		return err
+	}
+
+	err = deleteContext(ctx, c.client, entryPath(entry, queryString))
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Exports returns the list of Exports
+func (c *Client) Exports(params *ExportQueryParams) ([]Export, error) {
+	return c.ExportsContext(context.Background(), params)
+}
+
+// ExportsContext is like Exports but uses ctx for the request
+func (c *Client) ExportsContext(
+	ctx context.Context, params *ExportQueryParams,
+) ([]Export, error) {
+	queryString := ""
+
+	if params != nil {
+		queryString = params.getQueryString()
+	}
+
+	res, err := getContext(ctx, c.client, "exports", queryString)
+	if err != nil {
+		return nil, err
+	}
+
+	var exports []Export
+
+	err = c.decode(ctx, res, &exports)
+	if err != nil {
+		return nil, err
+	}
+
+	return exports, nil
+}
+
+// GetExport returns a specific Export, to poll its Status
+func (c *Client) GetExport(exportID string) (*Export, error) {
+	return c.GetExportContext(context.Background(), exportID)
+}
+
+// GetExportContext is like GetExport but uses ctx for the request
+func (c *Client) GetExportContext(
+	ctx context.Context, exportID string,
+) (*Export, error) {
+	res, err := getContext(
+		ctx, c.client, fmt.Sprintf("exports/%s", exportID), "")
+	if err != nil {
+		return nil, err
+	}
+
+	var export *Export
+
+	err = c.decode(ctx, res, &export)
+	if err != nil {
+		return nil, err
+	}
+
+	return export, nil
+}
+
+// CreateExport requests a Toshl Export, generated asynchronously by the
+// server
+func (c *Client) CreateExport(export *Export) error {
+	return c.CreateExportContext(context.Background(), export)
+}
+
+// CreateExportContext is like CreateExport but uses ctx for the request
+func (c *Client) CreateExportContext(
+	ctx context.Context, export *Export,
+) error {
+	if err := export.validate(); err != nil {
+		return err
+	}
+
+	jsonBytes, err := json.Marshal(export)
+	if err != nil {
+		return err
+	}
+
+	jsonStr := string(jsonBytes)
+
+	id, err := postContext(ctx, c.client, "exports", jsonStr)
+	if err != nil {
+		return err
+	}
+
+	export.ID = id
+
+	return nil
+}
+
+// WaitExport polls the Export every interval, which must be positive,
+// until it is done. It returns ErrExportFailed when the server could not
+// generate it.
+func (c *Client) WaitExport(
+	exportID string, interval time.Duration,
+) (*Export, error) {
+	return c.WaitExportContext(context.Background(), exportID, interval)
+}
+
+// WaitExportContext is like WaitExport but uses ctx for the requests
+func (c *Client) WaitExportContext(
+	ctx context.Context, exportID string, interval time.Duration,
+) (*Export, error) {
+	if interval <= 0 {
+		return nil, errors.New("'interval' must be positive;")
+	}
+
+	for {
+		export, err := c.GetExportContext(ctx, exportID)
+		if err != nil {
+			return nil, err
+		}
+
+		switch export.Status {
+		case ExportReady:
+			return export, nil
+		case ExportFailed:
+			return export, ErrExportFailed
+		}
+
+		if err := sleep(ctx, interval); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// DownloadExport streams the file of a ready Export to w
+func (c *Client) DownloadExport(exportID string, w io.Writer) error {
+	return c.DownloadExportContext(context.Background(), exportID, w)
+}
+
+// DownloadExportContext is like DownloadExport but uses ctx for the
+// request
+func (c *Client) DownloadExportContext(
+	ctx context.Context, exportID string, w io.Writer,
+) error {
+	return download(ctx, c.client,
+		fmt.Sprintf("exports/%s/download", exportID), "", w)
+}
+
+// UploadImage uploads an image, e.g. a receipt photo, to be attached to
+// entries with AttachImage
+func (c *Client) UploadImage(fileName string, file io.Reader) (*Image, error) {
+	return c.UploadImageContext(context.Background(), fileName, file)
+}
+
+// UploadImageContext is like UploadImage but uses ctx for the request
+func (c *Client) UploadImageContext(
+	ctx context.Context, fileName string, file io.Reader,
+) (*Image, error) {
+	res, err := upload(ctx, c.client, "images", fileName, file)
+	if err != nil {
+		return nil, err
+	}
+
+	var image *Image
+
+	err = c.decode(ctx, res, &image)
+	if err != nil {
+		return nil, err
+	}
+
+	return image, nil
+}
+
+// GetImage returns a specific Image
+func (c *Client) GetImage(imageID string) (*Image, error) {
+	return c.GetImageContext(context.Background(), imageID)
+}
+
+// GetImageContext is like GetImage but uses ctx for the request
+func (c *Client) GetImageContext(
+	ctx context.Context, imageID string,
+) (*Image, error) {
+	res, err := getContext(
+		ctx, c.client, fmt.Sprintf("images/%s", imageID), "")
+	if err != nil {
+		return nil, err
+	}
+
+	var image *Image
+
+	err = c.decode(ctx, res, &image)
+	if err != nil {
+		return nil, err
+	}
+
+	return image, nil
+}
+
+// AttachImage attaches an uploaded Image to the Entry and updates it. For
+// repeating entries only this one is updated.
+func (c *Client) AttachImage(entry *Entry, image *Image) error {
+	return c.AttachImageContext(context.Background(), entry, image)
+}
+
+// AttachImageContext is like AttachImage but uses ctx for the request
+func (c *Client) AttachImageContext(
+	ctx context.Context, entry *Entry, image *Image,
+) error {
+	for _, attached := range entry.Images {
+		if attached.Id == image.Id {
+			return nil
+		}
+	}
+
+	entry.Images = append(entry.Images, *image)
+
+	return c.UpdateEntryContext(ctx, entry, RepeatOne)
+}
+
+// DownloadImage streams the file of an Image to w
+func (c *Client) DownloadImage(imageID string, w io.Writer) error {
+	return c.DownloadImageContext(context.Background(), imageID, w)
+}
+
+// DownloadImageContext is like DownloadImage but uses ctx for the request
+func (c *Client) DownloadImageContext(
+	ctx context.Context, imageID string, w io.Writer,
+) error {
+	return download(ctx, c.client,
+		fmt.Sprintf("images/%s/download", imageID), "", w)
+}
+
+// entryPath returns the API endpoint of entry
+func entryPath(entry *Entry, queryString string) string {
+	return withQueryString(
+		fmt.Sprintf("entries/%s", stringValue(entry.Id)), queryString)
+}
+
+// withQueryString appends queryString to path, the HTTPClient Update and
+// Delete calls taking the query string as part of the API endpoint
+func withQueryString(path, queryString string) string {
+	if queryString != "" {
+		path = path + "?" + queryString
+	}
+
+	return path
+}
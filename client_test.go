@@ -0,0 +1,301 @@
+package toshl_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Philanthropists/toshl-go"
+	"github.com/Philanthropists/toshl-go/toshltest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordedRequest is a request received by the test server
+type recordedRequest struct {
+	Method string
+	Path   string
+	Query  string
+	Body   string
+}
+
+// newTestClient returns a Client backed by a server answering every
+// request with status and body, and the list of requests it received
+func newTestClient(
+	t *testing.T, status int, body string,
+) (*toshl.Client, *[]recordedRequest) {
+	var requests []recordedRequest
+	var server *httptest.Server
+
+	server = httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			payload, _ := io.ReadAll(r.Body)
+			requests = append(requests, recordedRequest{
+				Method: r.Method,
+				Path:   r.URL.Path,
+				Query:  r.URL.RawQuery,
+				Body:   string(payload),
+			})
+
+			w.Header().Set("Location", server.URL+"/created/42")
+			w.WriteHeader(status)
+			w.Write([]byte(body))
+		}))
+	t.Cleanup(server.Close)
+
+	httpClient := &toshl.RestHTTPClient{
+		BaseURL: server.URL,
+		Client:  server.Client(),
+	}
+
+	return toshl.NewClient("token", httpClient), &requests
+}
+
+func TestClientEntriesInvalidParams(t *testing.T) {
+	c := toshl.NewClient("token", nil)
+
+	entries, err := c.Entries(&toshl.EntryQueryParams{})
+	assert.NotNil(t, err)
+	assert.Nil(t, entries)
+}
+
+func TestClientGetEntry(t *testing.T) {
+	c, requests := newTestClient(t, http.StatusOK, `{
+        "id": "42",
+        "amount": -13.5,
+        "currency": {"code": "EUR"},
+        "date": "2021-03-04",
+        "account": "1",
+        "category": "2",
+        "created": "2021-03-04T10:00:00Z"
+    }`)
+
+	entry, err := c.GetEntry("42")
+	assert.Nil(t, err)
+	assert.Equal(t, "42", *entry.Id)
+	assert.Equal(t, "/entries/42", (*requests)[0].Path)
+}
+
+func TestClientUpdateEntry(t *testing.T) {
+	c, requests := newTestClient(t, http.StatusOK, `{
+        "id": "42",
+        "amount": -20,
+        "currency": {"code": "EUR"},
+        "date": "2021-03-04",
+        "account": "1",
+        "category": "2",
+        "created": "2021-03-04T10:00:00Z",
+        "modified": "2021-03-05T10:00:00Z"
+    }`)
+
+	id := "42"
+	entry := &toshl.Entry{
+		Id:     &id,
+		Amount: toshl.NewAmount(-20, 0),
+		Repeat: &toshl.Repeat{Frequency: toshl.Monthly},
+	}
+
+	err := c.UpdateEntry(entry, toshl.RepeatAll)
+	assert.Nil(t, err)
+	assert.Equal(t, "PUT", (*requests)[0].Method)
+	assert.Equal(t, "/entries/42", (*requests)[0].Path)
+	assert.Equal(t, "update=all", (*requests)[0].Query)
+	assert.Equal(t, "2021-03-05T10:00:00Z", *entry.Modified)
+}
+
+func TestClientDeleteEntry(t *testing.T) {
+	c, requests := newTestClient(t, http.StatusNoContent, "")
+
+	id := "42"
+	err := c.DeleteEntry(&toshl.Entry{Id: &id}, toshl.RepeatTail)
+	assert.Nil(t, err)
+	assert.Equal(t, "DELETE", (*requests)[0].Method)
+	assert.Equal(t, "/entries/42", (*requests)[0].Path)
+	assert.Equal(t, "", (*requests)[0].Query)
+}
+
+func TestClientDeleteEntryNotFound(t *testing.T) {
+	c, _ := newTestClient(t, http.StatusNotFound, `{
+        "error_id": "error.object.not_found"
+    }`)
+
+	id := "42"
+	err := c.DeleteEntry(&toshl.Entry{Id: &id}, "")
+	assert.True(t, toshl.IsNotFound(err))
+}
+
+func TestClientGetTransferEntry(t *testing.T) {
+	c, _ := newTestClient(t, http.StatusOK, `{
+        "id": "43",
+        "amount": -100,
+        "currency": {"code": "EUR"},
+        "date": "2021-03-04",
+        "account": "1",
+        "created": "2021-03-04T10:00:00Z",
+        "transaction": {
+            "id": "44",
+            "amount": 118.5,
+            "account": "2",
+            "currency": {"code": "USD"}
+        }
+    }`)
+
+	entry, err := c.GetEntry("43")
+	assert.Nil(t, err)
+	assert.True(t, entry.IsTransfer())
+	assert.Equal(t, "2", entry.Transaction.Account)
+	assert.Equal(t, "118.5", entry.Transaction.Amount.String())
+	assert.Equal(t, "USD", entry.Transaction.Currency.Code)
+}
+
+func TestClientCreateTransfer(t *testing.T) {
+	c, requests := newTestClient(t, http.StatusCreated, "")
+
+	fromID, toID := "1", "2"
+	from := &toshl.Account{ID: &fromID, Currency: &toshl.Currency{Code: "EUR"}}
+	to := &toshl.Account{ID: &toID, Currency: &toshl.Currency{Code: "USD"}}
+	received := toshl.NewAmount(11850, 2)
+
+	entry, err := c.CreateTransfer(from, to, toshl.NewAmount(100, 0),
+		&toshl.TransferOptions{
+			Date:     toshl.Date(time.Date(2021, 3, 4, 0, 0, 0, 0, time.UTC)),
+			ToAmount: &received,
+		})
+	assert.Nil(t, err)
+	assert.Equal(t, "42", *entry.Id)
+	assert.True(t, entry.IsTransfer())
+
+	var payload map[string]any
+	assert.Nil(t, json.Unmarshal([]byte((*requests)[0].Body), &payload))
+	assert.Equal(t, "/entries", (*requests)[0].Path)
+	assert.Equal(t, -100.0, payload["amount"])
+	assert.Equal(t, "2021-03-04", payload["date"])
+	assert.Equal(t, "1", payload["account"])
+	assert.NotContains(t, payload, "category")
+	assert.Equal(t, map[string]any{
+		"amount":   118.5,
+		"account":  "2",
+		"currency": map[string]any{"code": "USD"},
+	}, payload["transaction"])
+}
+
+func TestClientCreateTransferRequiresToAmount(t *testing.T) {
+	c, requests := newTestClient(t, http.StatusCreated, "")
+
+	fromID, toID := "1", "2"
+	from := &toshl.Account{ID: &fromID, Currency: &toshl.Currency{Code: "EUR"}}
+	to := &toshl.Account{ID: &toID, Currency: &toshl.Currency{Code: "USD"}}
+
+	_, err := c.CreateTransfer(from, to, toshl.NewAmount(100, 0), nil)
+	assert.NotNil(t, err)
+	assert.Empty(t, *requests)
+}
+
+func TestClientSplitEntry(t *testing.T) {
+	server := toshltest.NewServer()
+	defer server.Close()
+
+	c := server.NewClient()
+
+	description := "Supermarket"
+	parent := &toshl.Entry{
+		Amount:      toshl.NewAmount(-3000, 2),
+		Currency:    toshl.Currency{Code: "EUR"},
+		Date:        "2024-03-05",
+		Account:     "1",
+		Description: &description,
+	}
+
+	err := c.CreateSplitEntry(parent, []toshl.Entry{
+		{Amount: toshl.NewAmount(-1850, 2), Category: "food"},
+		{Amount: toshl.NewAmount(-1150, 2), Category: "household"},
+	})
+	require.NoError(t, err)
+	require.True(t, parent.IsSplit())
+
+	got, children, err := c.GetSplit(parent.Split.Children[1])
+	require.NoError(t, err)
+	assert.Equal(t, *parent.Id, *got.Id)
+	require.Len(t, children, 2)
+	assert.Equal(t, "food", children[0].Category)
+	assert.Equal(t, "2024-03-05", children[1].Date)
+	assert.Equal(t, *parent.Id, children[1].Split.Parent)
+
+	require.NoError(t, c.UnsplitEntry(got))
+	assert.False(t, got.IsSplit())
+	assert.Equal(t, 1, server.Count("entries"))
+}
+
+func TestClientSplitEntryChildFails(t *testing.T) {
+	server := toshltest.NewServer()
+	defer server.Close()
+
+	c := server.NewClient()
+
+	parent := &toshl.Entry{
+		Amount:   toshl.NewAmount(-30, 0),
+		Currency: toshl.Currency{Code: "EUR"},
+		Date:     "2024-03-05",
+		Account:  "1",
+	}
+
+	err := c.CreateSplitEntry(parent, []toshl.Entry{
+		{Amount: toshl.NewAmount(-10, 0), Category: "food"},
+		{Amount: toshl.NewAmount(-15, 0), Category: "household"},
+		{
+			Amount: toshl.NewAmount(-5, 0),
+			Repeat: &toshl.Repeat{Frequency: toshl.Monthly},
+		},
+	})
+	assert.NotNil(t, err)
+	assert.Nil(t, parent.Id)
+	assert.Equal(t, 0, server.Count("entries"))
+}
+
+func TestClientSplitEntryMismatch(t *testing.T) {
+	c, requests := newTestClient(t, http.StatusCreated, "")
+
+	err := c.CreateSplitEntry(&toshl.Entry{Amount: toshl.NewAmount(-30, 0)},
+		[]toshl.Entry{
+			{Amount: toshl.NewAmount(-20, 0)},
+			{Amount: toshl.NewAmount(-5, 0)},
+		})
+	assert.ErrorIs(t, err, toshl.ErrSplitMismatch)
+	assert.Empty(t, *requests)
+}
+
+func TestClientImages(t *testing.T) {
+	server := toshltest.NewServer()
+	defer server.Close()
+
+	c := server.NewClient()
+
+	receipt := []byte("\x89PNG\r\n\x1a\n receipt")
+
+	image, err := c.UploadImage("receipt.png", bytes.NewReader(receipt))
+	require.NoError(t, err)
+	assert.Equal(t, "receipt.png", image.Filename)
+	assert.Equal(t, "image/png", image.Type)
+
+	entry := &toshl.Entry{
+		Amount:   toshl.NewAmount(-1250, 2),
+		Currency: toshl.Currency{Code: "EUR"},
+		Date:     "2024-03-05",
+		Account:  "1",
+	}
+	require.NoError(t, c.CreateEntry(entry))
+	require.NoError(t, c.AttachImage(entry, image))
+
+	got, err := c.GetEntry(*entry.Id)
+	require.NoError(t, err)
+	require.Len(t, got.Images, 1)
+	assert.Equal(t, image.Id, got.Images[0].Id)
+
+	var file bytes.Buffer
+	require.NoError(t, c.DownloadImage(got.Images[0].Id, &file))
+	assert.Equal(t, receipt, file.Bytes())
+}
@@ -14,15 +14,15 @@ type Currency struct {
 
 // Median represents a Toshl median
 type Median struct {
-	Expenses float64 `json:"expenses"`
-	Incomes  float64 `json:"incomes"`
+	Expenses Amount `json:"expenses"`
+	Incomes  Amount `json:"incomes"`
 }
 
 // Goal represents a Toshl goal
 type Goal struct {
-	Amount float64 `json:"amount"`
-	Start  string  `json:"start"`
-	End    string  `json:"end"`
+	Amount Amount `json:"amount"`
+	Start  string `json:"start"`
+	End    string `json:"end"`
 }
 
 // Recurrence represents a Toshl recurrence
@@ -41,6 +41,9 @@ type CategoryCounts struct {
 
 const DateFormat = "2006-01-02"
 
+// sinceFormat is the format of the since filter of every list endpoint
+const sinceFormat = "2006-01-02T15:04:05Z"
+
 type Date time.Time
 
 func (v Date) MarshalJSON() ([]byte, error) {
@@ -0,0 +1,197 @@
+package toshl
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"sync"
+	"time"
+)
+
+// CurrencyInfo represents a currency supported by Toshl
+type CurrencyInfo struct {
+	Code      string `json:"code"`
+	Name      string `json:"name"`
+	Symbol    string `json:"symbol"`
+	Precision int    `json:"precision"`
+	Type      string `json:"type"`
+	Rate      Amount `json:"rate"`
+	Modified  string `json:"modified"`
+}
+
+// currencyList decodes the currencies listing, which the API returns as
+// an object keyed by currency code
+type currencyList []CurrencyInfo
+
+func (l *currencyList) UnmarshalJSON(b []byte) error {
+	if bytes.HasPrefix(bytes.TrimSpace(b), []byte("[")) {
+		return json.Unmarshal(b, (*[]CurrencyInfo)(l))
+	}
+
+	var byCode map[string]CurrencyInfo
+	if err := json.Unmarshal(b, &byCode); err != nil {
+		return err
+	}
+
+	*l = make(currencyList, 0, len(byCode))
+	for code, currency := range byCode {
+		if currency.Code == "" {
+			currency.Code = code
+		}
+		*l = append(*l, currency)
+	}
+
+	sort.Slice(*l, func(i, j int) bool { return (*l)[i].Code < (*l)[j].Code })
+
+	return nil
+}
+
+// CurrencyQueryParams represents a struct of parameters usable
+// to List Currencies
+type CurrencyQueryParams struct {
+	Since time.Time
+}
+
+func (c *CurrencyQueryParams) getQueryString() string {
+	v := url.Values{}
+
+	if !c.Since.IsZero() {
+		v.Set("since", c.Since.Format(sinceFormat))
+	}
+
+	return v.Encode()
+}
+
+// ExchangeRates represents the exchange rates of a given date, as the
+// amount of each currency worth one unit of Base
+type ExchangeRates struct {
+	Date  Date              `json:"date"`
+	Base  string            `json:"base"`
+	Rates map[string]Amount `json:"rates"`
+}
+
+// rate returns the amount of currency worth one unit of Base
+func (r *ExchangeRates) rate(currency string) (Amount, bool) {
+	if currency == r.Base {
+		return NewAmount(1, 0), true
+	}
+
+	rate, ok := r.Rates[currency]
+	if !ok || rate.Sign() <= 0 {
+		return Amount{}, false
+	}
+
+	return rate, true
+}
+
+// ErrRateNotFound is returned when converting a currency without a known
+// exchange rate
+var ErrRateNotFound = errors.New("toshl: exchange rate not found")
+
+// RateSource provides the exchange rates of a given date. Client is a
+// RateSource.
+type RateSource interface {
+	RatesContext(ctx context.Context, date Date) (*ExchangeRates, error)
+}
+
+// Converter converts amounts between currencies using the exchange rates
+// of a given date. Rates are fetched once per date from its RateSource and
+// cached, and can be preloaded with AddRates to convert offline. It is
+// safe for concurrent use.
+type Converter struct {
+	source RateSource
+
+	mu    sync.Mutex
+	rates map[string]*ExchangeRates
+}
+
+// NewConverter returns a Converter fetching missing rates from source,
+// which may be nil to only use rates added with AddRates
+func NewConverter(source RateSource) *Converter {
+	return &Converter{
+		source: source,
+		rates:  map[string]*ExchangeRates{},
+	}
+}
+
+// AddRates caches rates for their date
+func (c *Converter) AddRates(rates *ExchangeRates) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.rates[rates.Date.String()] = rates
+}
+
+func (c *Converter) ratesFor(
+	ctx context.Context, date Date,
+) (*ExchangeRates, error) {
+	c.mu.Lock()
+	rates, ok := c.rates[date.String()]
+	c.mu.Unlock()
+
+	if ok {
+		return rates, nil
+	}
+
+	if c.source == nil {
+		return nil, fmt.Errorf("%w for %s", ErrRateNotFound, date)
+	}
+
+	rates, err := c.source.RatesContext(ctx, date)
+	if err != nil {
+		return nil, err
+	}
+
+	if rates.Date == (Date{}) {
+		rates.Date = date
+	}
+
+	c.AddRates(rates)
+
+	return rates, nil
+}
+
+// Convert converts amount from one currency to another using the rates of
+// date. The result is rounded to the precision of the target currency.
+func (c *Converter) Convert(
+	ctx context.Context, amount Amount, from, to string, date Date,
+) (Amount, error) {
+	if from == to {
+		return amount, nil
+	}
+
+	rates, err := c.ratesFor(ctx, date)
+	if err != nil {
+		return Amount{}, err
+	}
+
+	fromRate, ok := rates.rate(from)
+	if !ok {
+		return Amount{}, fmt.Errorf(
+			"%w for %s on %s", ErrRateNotFound, from, date)
+	}
+
+	toRate, ok := rates.rate(to)
+	if !ok {
+		return Amount{}, fmt.Errorf(
+			"%w for %s on %s", ErrRateNotFound, to, date)
+	}
+
+	return amount.Mul(toRate).Div(fromRate, CurrencyPrecision(to)), nil
+}
+
+// ConvertMoney is like Convert for a Money value
+func (c *Converter) ConvertMoney(
+	ctx context.Context, m Money, to string, date Date,
+) (Money, error) {
+	amount, err := c.Convert(ctx, m.Amount, m.Currency, to, date)
+	if err != nil {
+		return Money{}, err
+	}
+
+	return Money{Amount: amount, Currency: to}, nil
+}
@@ -0,0 +1,88 @@
+package toshl_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/Philanthropists/toshl-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClientCurrencies(t *testing.T) {
+	c, requests := newTestClient(t, http.StatusOK, `{
+        "USD": {"name": "US Dollar", "symbol": "$", "precision": 2,
+                "rate": 1},
+        "EUR": {"name": "Euro", "symbol": "€", "precision": 2,
+                "rate": 0.9}
+    }`)
+
+	currencies, err := c.Currencies(&toshl.CurrencyQueryParams{
+		Since: time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC),
+	})
+	assert.Nil(t, err)
+	assert.Len(t, currencies, 2)
+	assert.Equal(t, "EUR", currencies[0].Code)
+	assert.Equal(t, "0.9", currencies[0].Rate.String())
+	assert.Equal(t, "since=2020-01-02T03%3A04%3A05Z", (*requests)[0].Query)
+}
+
+func testDate(year int, month time.Month, day int) toshl.Date {
+	return toshl.Date(time.Date(year, month, day, 0, 0, 0, 0, time.UTC))
+}
+
+func TestConverterOffline(t *testing.T) {
+	converter := toshl.NewConverter(nil)
+	converter.AddRates(&toshl.ExchangeRates{
+		Date: testDate(2021, 3, 1),
+		Base: "USD",
+		Rates: map[string]toshl.Amount{
+			"EUR": mustParseAmount(t, "0.8"),
+			"JPY": mustParseAmount(t, "110"),
+		},
+	})
+
+	ctx := context.Background()
+
+	eur, err := converter.Convert(ctx, mustParseAmount(t, "10"),
+		"USD", "EUR", testDate(2021, 3, 1))
+	assert.Nil(t, err)
+	assert.Equal(t, "8.00", eur.String())
+
+	jpy, err := converter.ConvertMoney(ctx, toshl.Money{
+		Amount: mustParseAmount(t, "12.34"), Currency: "EUR",
+	}, "JPY", testDate(2021, 3, 1))
+	assert.Nil(t, err)
+	assert.Equal(t, "1697 JPY", jpy.String())
+
+	_, err = converter.Convert(ctx, mustParseAmount(t, "10"),
+		"USD", "GBP", testDate(2021, 3, 1))
+	assert.ErrorIs(t, err, toshl.ErrRateNotFound)
+
+	_, err = converter.Convert(ctx, mustParseAmount(t, "10"),
+		"USD", "EUR", testDate(2021, 3, 2))
+	assert.ErrorIs(t, err, toshl.ErrRateNotFound)
+}
+
+func TestConverterCachesRates(t *testing.T) {
+	c, requests := newTestClient(t, http.StatusOK, `{
+        "date": "2021-03-01",
+        "base": "USD",
+        "rates": {"EUR": 0.8}
+    }`)
+
+	converter := toshl.NewConverter(c)
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		usd, err := converter.Convert(ctx, mustParseAmount(t, "8"),
+			"EUR", "USD", testDate(2021, 3, 1))
+		assert.Nil(t, err)
+		assert.Equal(t, "10.00", usd.String())
+	}
+
+	assert.Len(t, *requests, 1)
+	assert.Equal(t, "/currencies/rates", (*requests)[0].Path)
+	assert.Equal(t, "date=2021-03-01", (*requests)[0].Query)
+}
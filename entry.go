@@ -2,28 +2,75 @@ package toshl
 
 import (
 	"errors"
+	"fmt"
 	"net/url"
+	"strconv"
+	"strings"
 	"time"
 )
 
 type Entry struct {
-	Id          *string   `json:"id,omitempty"`
-	Amount      float64   `json:"amount"`
-	Currency    Currency  `json:"currency"`
-	Date        string    `json:"date"`
-	Description *string   `json:"desc,omitempty"`
-	Account     string    `json:"account"`
-	Category    string    `json:"category"`
-	Tags        []string  `json:"tags,omitempty"`
-	Location    *Location `json:"location,omitempty"`
-	Created     time.Time `json:"created"`
-	Modified    *string   `json:"modified,omitempty"`
-	Repeat      *Repeat   `json:"repeat,omitempty"`
+	Id          *string                `json:"id,omitempty"`
+	Amount      Amount                 `json:"amount"`
+	Currency    Currency               `json:"currency"`
+	Date        string                 `json:"date"`
+	Description *string                `json:"desc,omitempty"`
+	Account     string                 `json:"account"`
+	Category    string                 `json:"category,omitempty"`
+	Tags        []string               `json:"tags,omitempty"`
+	Location    *Location              `json:"location,omitempty"`
+	Created     time.Time              `json:"created"`
+	Modified    *string                `json:"modified,omitempty"`
+	Repeat      *Repeat                `json:"repeat,omitempty"`
+	Transaction *Transaction           `json:"transaction,omitempty"`
+	Split       *Split                 `json:"split,omitempty"`
+	Images      []Image                `json:"images,omitempty"`
+	Reminders   []Reminder             `json:"reminders,omitempty"`
+	Import      *Import                `json:"import,omitempty"`
+	Review      *Review                `json:"review,omitempty"`
+	Settle      *Settle                `json:"settle,omitempty"`
+	Readonly    []string               `json:"readonly,omitempty"`
+	Completed   bool                   `json:"completed,omitempty"`
+	Deleted     bool                   `json:"deleted,omitempty"`
+	Extra       map[string]interface{} `json:"extra,omitempty"`
 }
 
+// IsTransfer reports whether the entry is a transfer between accounts
+func (e *Entry) IsTransfer() bool {
+	return e.Transaction != nil
+}
+
+// EntryType represents the kind of a Toshl entry
+type EntryType string
+
+const (
+	EntryExpense     EntryType = "expense"
+	EntryIncome      EntryType = "income"
+	EntryTransaction EntryType = "transaction"
+)
+
+// maxEntriesPerPage is the largest page size allowed by the API
+const maxEntriesPerPage = 500
+
+// EntryQueryParams represents a struct of parameters usable
+// to List Entries. From and To are mandatory unless Since is set.
 type EntryQueryParams struct {
-	From Date
-	To   Date
+	Page           int
+	PerPage        int
+	From           Date
+	To             Date
+	Since          time.Time
+	Type           EntryType
+	Accounts       []string
+	Categories     []string
+	Tags           []string
+	Locations      []string
+	Search         string
+	IncludeDeleted bool
+	Expand         bool
+	Repeat         string
+	Parent         string
+	HasImages      bool
 }
 
 func (a *EntryQueryParams) getQueryString() (string, error) {
@@ -33,16 +80,90 @@ func (a *EntryQueryParams) getQueryString() (string, error) {
 	var errMsg string
 
 	if a.From == nilDate {
-		errMsg = errMsg + "'from' field is mandatory;"
+		if a.Since.IsZero() {
+			errMsg = errMsg + "'from' field is mandatory;"
+		}
+	} else {
+		v.Set("from", a.From.String())
 	}
 
-	v.Set("from", a.From.String())
-
 	if a.To == nilDate {
-		errMsg = errMsg + "'to' field is mandatory;"
+		if a.Since.IsZero() {
+			errMsg = errMsg + "'to' field is mandatory;"
+		}
+	} else {
+		v.Set("to", a.To.String())
+	}
+
+	if a.From != nilDate && a.To != nilDate &&
+		time.Time(a.From).After(time.Time(a.To)) {
+		errMsg = errMsg + "'from' must not be after 'to';"
+	}
+
+	if a.Page > 0 {
+		v.Set("page", strconv.Itoa(a.Page))
+	}
+
+	if a.PerPage > maxEntriesPerPage {
+		errMsg = errMsg + fmt.Sprintf(
+			"'per_page' must not exceed %d;", maxEntriesPerPage)
 	}
 
-	v.Set("to", a.To.String())
+	if a.PerPage > 0 {
+		v.Set("per_page", strconv.Itoa(a.PerPage))
+	}
+
+	if !a.Since.IsZero() {
+		v.Set("since", a.Since.Format(sinceFormat))
+	}
+
+	switch a.Type {
+	case "":
+	case EntryExpense, EntryIncome, EntryTransaction:
+		v.Set("type", string(a.Type))
+	default:
+		errMsg = errMsg + fmt.Sprintf("invalid 'type' %q;", a.Type)
+	}
+
+	if len(a.Accounts) > 0 {
+		v.Set("accounts", strings.Join(a.Accounts, ","))
+	}
+
+	if len(a.Categories) > 0 {
+		v.Set("categories", strings.Join(a.Categories, ","))
+	}
+
+	if len(a.Tags) > 0 {
+		v.Set("tags", strings.Join(a.Tags, ","))
+	}
+
+	if len(a.Locations) > 0 {
+		v.Set("locations", strings.Join(a.Locations, ","))
+	}
+
+	if a.Search != "" {
+		v.Set("search", a.Search)
+	}
+
+	if a.IncludeDeleted {
+		v.Set("include_deleted", strconv.FormatBool(a.IncludeDeleted))
+	}
+
+	if a.Expand {
+		v.Set("expand", strconv.FormatBool(a.Expand))
+	}
+
+	if a.Repeat != "" {
+		v.Set("repeat", a.Repeat)
+	}
+
+	if a.Parent != "" {
+		v.Set("parent", a.Parent)
+	}
+
+	if a.HasImages {
+		v.Set("images", strconv.FormatBool(a.HasImages))
+	}
 
 	if errMsg != "" {
 		return "", errors.New(errMsg)
@@ -68,7 +189,45 @@ const (
 	Confirmed RepeatType = "confirmed"
 )
 
+// RepeatMode selects which items of a repeating series, entries or budget
+// iterations, are updated or deleted
+type RepeatMode string
+
+const (
+	// RepeatOne changes only the given item
+	RepeatOne RepeatMode = "one"
+	// RepeatTail changes the given item and every following one
+	RepeatTail RepeatMode = "tail"
+	// RepeatAll changes every item of the series
+	RepeatAll RepeatMode = "all"
+)
+
+// repeatQueryString returns the query string selecting which items of the
+// series are changed, param being either "update" or "delete". Items that
+// are not repeating are not part of a series so no mode is sent.
+func repeatQueryString(
+	param string, mode RepeatMode, repeating bool,
+) (string, error) {
+	if !repeating {
+		return "", nil
+	}
+
+	switch mode {
+	case "":
+		mode = RepeatOne
+	case RepeatOne, RepeatTail, RepeatAll:
+	default:
+		return "", fmt.Errorf("invalid repeat mode %q", mode)
+	}
+
+	v := url.Values{}
+	v.Set(param, string(mode))
+
+	return v.Encode(), nil
+}
+
 type Repeat struct {
+	Id         string          `json:"id,omitempty"`
 	Start      Date            `json:"start"`
 	End        Date            `json:"end"`
 	Frequency  RepeatFrequency `json:"frequency"`
@@ -83,29 +242,112 @@ type Repeat struct {
 	Type       RepeatType      `json:"type"`
 }
 
+// Transaction is the receiving side of a transfer Entry, the Entry being
+// the sending side. Amount and Currency differ from the Entry ones for
+// transfers between accounts in different currencies.
+//
+// The other fields belong to the Entry, the API never sets them on the
+// Transaction. They are kept for compatibility and no longer encoded.
 type Transaction struct {
-	Id        string                 `json:"id"`
-	Amount    float64                `json:"amount"`
-	Account   string                 `json:"account,omitempty"`
-	Currency  Currency               `json:"currency,omitempty"`
-	Images    []Image                `json:"images"`
-	Reminders []Reminder             `json:"reminders"`
-	Import    Import                 `json:"import"`
-	Review    Review                 `json:"review"`
-	Settle    Settle                 `json:"settle"`
-	Split     Split                  `json:"split"`
-	Readonly  []string               `json:"readonly"`
-	Completed bool                   `json:"completed"`
-	Deleted   bool                   `json:"deleted"`
-	Extra     map[string]interface{} `json:"extra"`
+	Id       string   `json:"id,omitempty"`
+	Amount   Amount   `json:"amount"`
+	Account  string   `json:"account"`
+	Currency Currency `json:"currency"`
+
+	// Deprecated: use Entry.Images instead.
+	Images []Image `json:"-"`
+	// Deprecated: use Entry.Reminders instead.
+	Reminders []Reminder `json:"-"`
+	// Deprecated: use Entry.Import instead.
+	Import Import `json:"-"`
+	// Deprecated: use Entry.Review instead.
+	Review Review `json:"-"`
+	// Deprecated: use Entry.Settle instead.
+	Settle Settle `json:"-"`
+	// Deprecated: use Entry.Split instead.
+	Split Split `json:"-"`
+	// Deprecated: use Entry.Readonly instead.
+	Readonly []string `json:"-"`
+	// Deprecated: use Entry.Completed instead.
+	Completed bool `json:"-"`
+	// Deprecated: use Entry.Deleted instead.
+	Deleted bool `json:"-"`
+	// Deprecated: use Entry.Extra instead.
+	Extra map[string]interface{} `json:"-"`
+}
+
+// TransferOptions holds the optional fields of a transfer
+type TransferOptions struct {
+	// Date defaults to today
+	Date Date
+
+	// ToAmount is the amount received, required when the accounts have
+	// different currencies
+	ToAmount *Amount
+
+	Description string
+	Tags        []string
+}
+
+// transferEntry builds the Entry sending amount from one account to the
+// other
+func transferEntry(
+	from, to *Account, amount Amount, opts *TransferOptions,
+) (*Entry, error) {
+	if opts == nil {
+		opts = &TransferOptions{}
+	}
+
+	if from.ID == nil || to.ID == nil {
+		return nil, errors.New("toshl: transfer accounts must have an ID")
+	}
+
+	if from.Currency == nil || to.Currency == nil {
+		return nil, errors.New("toshl: transfer accounts must have a currency")
+	}
+
+	toAmount := amount.Abs()
+	if opts.ToAmount != nil {
+		toAmount = opts.ToAmount.Abs()
+	} else if from.Currency.Code != to.Currency.Code {
+		return nil, fmt.Errorf(
+			"toshl: transfer from %s to %s requires ToAmount",
+			from.Currency.Code, to.Currency.Code)
+	}
+
+	date := time.Time(opts.Date)
+	if date.IsZero() {
+		date = time.Now()
+	}
+
+	entry := &Entry{
+		Amount:   amount.Abs().Neg(),
+		Currency: Currency{Code: from.Currency.Code},
+		Date:     date.Format(DateFormat),
+		Account:  *from.ID,
+		Tags:     opts.Tags,
+		Transaction: &Transaction{
+			Amount:   toAmount,
+			Account:  *to.ID,
+			Currency: Currency{Code: to.Currency.Code},
+		},
+	}
+
+	if opts.Description != "" {
+		entry.Description = &opts.Description
+	}
+
+	return entry, nil
 }
 
+// Image is a file, e.g. a receipt photo, uploaded with UploadImage and
+// attached to entries. Only the Id matters when attaching it.
 type Image struct {
 	Id       string `json:"id"`
-	Path     string `json:"path"`
-	Filename string `json:"filename"`
-	Type     string `json:"type"`
-	Status   string `json:"status"`
+	Path     string `json:"path,omitempty"`
+	Filename string `json:"filename,omitempty"`
+	Type     string `json:"type,omitempty"`
+	Status   string `json:"status,omitempty"`
 }
 
 type Reminder struct {
@@ -128,7 +370,44 @@ type Settle struct {
 	Id string `json:"id"`
 }
 
+// Split links the parts of an entry split into several categories: the
+// parent holds the IDs of its children, each child the ID of its parent
 type Split struct {
-	Parent   string   `json:"parent"`
-	Children []string `json:"children"`
+	Parent   string   `json:"parent,omitempty"`
+	Children []string `json:"children,omitempty"`
+}
+
+// IsSplit reports whether the entry is the parent of a split
+func (e *Entry) IsSplit() bool {
+	return e.Split != nil && len(e.Split.Children) > 0
+}
+
+// ErrSplitMismatch is returned when the amounts of split children do not
+// sum to the amount of their parent
+var ErrSplitMismatch = errors.New(
+	"toshl: split amounts do not sum to the entry amount")
+
+// validateSplit checks that children, in the currency of parent, sum to
+// its amount
+func validateSplit(parent *Entry, children []Entry) error {
+	if len(children) < 2 {
+		return errors.New("toshl: a split needs at least two children")
+	}
+
+	var sum Amount
+	for _, child := range children {
+		if child.Currency.Code != "" &&
+			child.Currency.Code != parent.Currency.Code {
+			return ErrCurrencyMismatch
+		}
+
+		sum = sum.Add(child.Amount)
+	}
+
+	if !sum.Equal(parent.Amount) {
+		return fmt.Errorf("%w: %s instead of %s",
+			ErrSplitMismatch, sum, parent.Amount)
+	}
+
+	return nil
 }
@@ -0,0 +1,113 @@
+package toshl
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRepeatQueryString(t *testing.T) {
+	q, err := repeatQueryString("update", RepeatAll, false)
+	assert.Nil(t, err)
+	assert.Equal(t, "", q)
+
+	q, err = repeatQueryString("update", "", true)
+	assert.Nil(t, err)
+	assert.Equal(t, "update=one", q)
+
+	q, err = repeatQueryString("delete", RepeatTail, true)
+	assert.Nil(t, err)
+	assert.Equal(t, "delete=tail", q)
+
+	_, err = repeatQueryString("delete", "some", true)
+	assert.NotNil(t, err)
+}
+
+func TestEntryGetQueryString(t *testing.T) {
+	e := EntryQueryParams{
+		Page:           2,
+		PerPage:        100,
+		From:           Date(time.Date(2016, 11, 1, 0, 0, 0, 0, time.UTC)),
+		To:             Date(time.Date(2016, 11, 30, 0, 0, 0, 0, time.UTC)),
+		Since:          time.Date(2016, 11, 6, 13, 28, 0, 0, time.Local),
+		Type:           EntryExpense,
+		Accounts:       []string{"id1", "id2"},
+		Categories:     []string{"cat1", "cat2"},
+		Tags:           []string{"tag1", "tag2"},
+		Locations:      []string{"loc1"},
+		Search:         "search_term",
+		IncludeDeleted: true,
+		Expand:         true,
+		Repeat:         "r1",
+		Parent:         "p1",
+		HasImages:      true,
+	}
+
+	q, err := e.getQueryString()
+	assert.Nil(t, err)
+	assert.Equal(t,
+		`accounts=id1%2Cid2&categories=cat1%2Ccat2&expand=true&`+
+			`from=2016-11-01&images=true&include_deleted=true&`+
+			`locations=loc1&page=2&parent=p1&per_page=100&repeat=r1&`+
+			`search=search_term&since=2016-11-06T13%3A28%3A00Z&`+
+			`tags=tag1%2Ctag2&to=2016-11-30&type=expense`,
+		q)
+}
+
+func TestEntryGetQueryStringSinceOnly(t *testing.T) {
+	e := EntryQueryParams{
+		Since: time.Date(2016, 11, 6, 13, 28, 0, 0, time.Local),
+	}
+
+	q, err := e.getQueryString()
+	assert.Nil(t, err)
+	assert.Equal(t, `since=2016-11-06T13%3A28%3A00Z`, q)
+}
+
+func TestEntryGetQueryStringValidation(t *testing.T) {
+	e := EntryQueryParams{
+		From:    Date(time.Date(2016, 12, 1, 0, 0, 0, 0, time.UTC)),
+		To:      Date(time.Date(2016, 11, 1, 0, 0, 0, 0, time.UTC)),
+		PerPage: 1000,
+		Type:    "refund",
+	}
+
+	_, err := e.getQueryString()
+	assert.EqualError(t, err,
+		`'from' must not be after 'to';'per_page' must not exceed 500;`+
+			`invalid 'type' "refund";`)
+
+	e = EntryQueryParams{}
+
+	_, err = e.getQueryString()
+	assert.EqualError(t, err,
+		`'from' field is mandatory;'to' field is mandatory;`)
+}
+
+func TestValidateSplit(t *testing.T) {
+	parent := &Entry{
+		Amount:   NewAmount(-3000, 2),
+		Currency: Currency{Code: "EUR"},
+	}
+
+	assert.Nil(t, validateSplit(parent, []Entry{
+		{Amount: NewAmount(-1850, 2)},
+		{Amount: NewAmount(-115, 1), Currency: Currency{Code: "EUR"}},
+	}))
+
+	err := validateSplit(parent, []Entry{
+		{Amount: NewAmount(-1850, 2)},
+		{Amount: NewAmount(-1000, 2)},
+	})
+	assert.ErrorIs(t, err, ErrSplitMismatch)
+	assert.Contains(t, err.Error(), "-28.50 instead of -30.00")
+
+	err = validateSplit(parent, []Entry{
+		{Amount: NewAmount(-1850, 2)},
+		{Amount: NewAmount(-1150, 2), Currency: Currency{Code: "USD"}},
+	})
+	assert.ErrorIs(t, err, ErrCurrencyMismatch)
+
+	assert.NotNil(t, validateSplit(parent, []Entry{{Amount: parent.Amount}}))
+}
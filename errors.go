@@ -0,0 +1,125 @@
+package toshl
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// APIError represents an error response returned by the Toshl API
+type APIError struct {
+	StatusCode  int          `json:"-"`
+	Method      string       `json:"-"`
+	Path        string       `json:"-"`
+	ErrorID     string       `json:"error_id"`
+	Description string       `json:"description"`
+	Fields      []FieldError `json:"fields,omitempty"`
+
+	// RetryAfter is the delay requested by the Retry-After header, if any
+	RetryAfter time.Duration `json:"-"`
+}
+
+// FieldError represents a validation error on a single field
+type FieldError struct {
+	Field       string `json:"field"`
+	ErrorID     string `json:"error_id"`
+	Description string `json:"description"`
+}
+
+func newAPIError(
+	method, path string, resp *http.Response, body []byte,
+) *APIError {
+	apiErr := &APIError{}
+
+	// The body is not always JSON (e.g. proxies returning HTML), in which
+	// case only the status is known
+	if err := json.Unmarshal(body, apiErr); err != nil {
+		apiErr = &APIError{}
+	}
+
+	apiErr.StatusCode = resp.StatusCode
+	apiErr.Method = method
+	apiErr.Path = path
+	apiErr.RetryAfter = parseRetryAfter(resp.Header.Get("Retry-After"))
+
+	return apiErr
+}
+
+func (e *APIError) Error() string {
+	msg := fmt.Sprintf("toshl: %s %s: %d %s",
+		e.Method, e.Path, e.StatusCode, http.StatusText(e.StatusCode))
+
+	if e.ErrorID != "" {
+		msg += ": " + e.ErrorID
+	}
+
+	if e.Description != "" {
+		msg += ": " + e.Description
+	}
+
+	for _, field := range e.Fields {
+		msg += fmt.Sprintf(" [%s: %s]", field.Field, field.ErrorID)
+	}
+
+	return msg
+}
+
+func asAPIError(err error) (*APIError, bool) {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr, true
+	}
+
+	return nil, false
+}
+
+func hasStatusCode(err error, statusCode int) bool {
+	apiErr, ok := asAPIError(err)
+	return ok && apiErr.StatusCode == statusCode
+}
+
+// IsNotFound reports whether err is an API error for a missing resource
+func IsNotFound(err error) bool {
+	return hasStatusCode(err, http.StatusNotFound)
+}
+
+// IsUnauthorized reports whether err is an API error caused by a missing
+// or invalid token
+func IsUnauthorized(err error) bool {
+	return hasStatusCode(err, http.StatusUnauthorized)
+}
+
+// IsValidation reports whether err is an API error caused by an invalid
+// request payload or query string
+func IsValidation(err error) bool {
+	apiErr, ok := asAPIError(err)
+	if !ok {
+		return false
+	}
+
+	switch apiErr.StatusCode {
+	case http.StatusUnprocessableEntity:
+		return true
+	case http.StatusBadRequest:
+		return len(apiErr.Fields) > 0 ||
+			strings.HasPrefix(apiErr.ErrorID, "error.validation")
+	}
+
+	return false
+}
+
+// IsRateLimited reports whether err is an API error caused by exceeding
+// the API rate limits
+func IsRateLimited(err error) bool {
+	return hasStatusCode(err, http.StatusTooManyRequests)
+}
+
+// IsConflict reports whether err is an API error caused by updating an
+// object with a stale modified value, someone else having changed it since
+// it was read
+func IsConflict(err error) bool {
+	return hasStatusCode(err, http.StatusConflict)
+}
@@ -0,0 +1,103 @@
+package toshl_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Philanthropists/toshl-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func newErrorServer(status int, body string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(status)
+			w.Write([]byte(body))
+		}))
+}
+
+func TestAPIErrorFromEveryVerb(t *testing.T) {
+	server := newErrorServer(http.StatusNotFound, `{
+        "error_id": "error.object.not_found",
+        "description": "Object with id 42 not found."
+    }`)
+	defer server.Close()
+
+	c := &toshl.RestHTTPClient{BaseURL: server.URL, Client: server.Client()}
+
+	_, getErr := c.Get("accounts/42", "")
+	_, multipleErr := c.GetMultiple("entries", "")
+	_, postErr := c.Post("accounts/42", "{}")
+	_, updateErr := c.Update("accounts/42", "{}")
+	deleteErr := c.Delete("accounts/42")
+
+	for _, err := range []error{
+		getErr, multipleErr, postErr, updateErr, deleteErr,
+	} {
+		assert.True(t, toshl.IsNotFound(err))
+		assert.False(t, toshl.IsUnauthorized(err))
+	}
+
+	var apiErr *toshl.APIError
+	assert.True(t, errors.As(deleteErr, &apiErr))
+	assert.Equal(t, http.StatusNotFound, apiErr.StatusCode)
+	assert.Equal(t, "DELETE", apiErr.Method)
+	assert.Equal(t, "/accounts/42", apiErr.Path)
+	assert.Equal(t, "error.object.not_found", apiErr.ErrorID)
+	assert.Equal(t, "Object with id 42 not found.", apiErr.Description)
+}
+
+func TestAPIErrorValidation(t *testing.T) {
+	server := newErrorServer(http.StatusBadRequest, `{
+        "error_id": "error.validation",
+        "description": "Validation failed.",
+        "fields": [{
+            "field": "name",
+            "error_id": "error.validation.required",
+            "description": "Name is required."
+        }]
+    }`)
+	defer server.Close()
+
+	c := &toshl.RestHTTPClient{BaseURL: server.URL, Client: server.Client()}
+
+	_, err := c.Post("categories", "{}")
+	assert.True(t, toshl.IsValidation(err))
+
+	var apiErr *toshl.APIError
+	assert.True(t, errors.As(fmt.Errorf("wrapped: %w", err), &apiErr))
+	assert.Len(t, apiErr.Fields, 1)
+	assert.Equal(t, "name", apiErr.Fields[0].Field)
+}
+
+func TestAPIErrorNonJSONBody(t *testing.T) {
+	server := newErrorServer(
+		http.StatusTooManyRequests, "<html>Too Many Requests</html>")
+	defer server.Close()
+
+	c := &toshl.RestHTTPClient{BaseURL: server.URL, Client: server.Client()}
+
+	res, err := c.Get("accounts", "")
+	assert.Equal(t, "", res)
+	assert.True(t, toshl.IsRateLimited(err))
+	assert.False(t, toshl.IsValidation(err))
+}
+
+func TestAPIErrorConflict(t *testing.T) {
+	server := newErrorServer(http.StatusConflict,
+		`{"error_id":"error.object.conflict","description":"Modified."}`)
+	defer server.Close()
+
+	client := toshl.NewClient("", &toshl.RestHTTPClient{
+		BaseURL: server.URL,
+		Client:  server.Client(),
+	})
+
+	err := client.UpdateAccount(&toshl.Account{})
+
+	assert.True(t, toshl.IsConflict(err))
+	assert.False(t, toshl.IsValidation(err))
+}
@@ -0,0 +1,106 @@
+package toshl
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// ExportFormat represents the file format of an Export
+type ExportFormat string
+
+const (
+	ExportCSV  ExportFormat = "csv"
+	ExportXLSX ExportFormat = "xlsx"
+	ExportPDF  ExportFormat = "pdf"
+)
+
+// ExportStatus represents the progress of an Export
+type ExportStatus string
+
+const (
+	ExportPending    ExportStatus = "pending"
+	ExportGenerating ExportStatus = "generating"
+	ExportReady      ExportStatus = "ready"
+	ExportFailed     ExportStatus = "failed"
+)
+
+// ErrExportFailed is returned when the server could not generate an Export
+var ErrExportFailed = errors.New("toshl: export failed")
+
+// Export represents a server-side export of entries
+type Export struct {
+	ID       string        `json:"id,omitempty"`
+	Format   ExportFormat  `json:"format"`
+	Filters  ExportFilters `json:"filters"`
+	Status   ExportStatus  `json:"status,omitempty"`
+	Created  string        `json:"created,omitempty"`
+	Modified string        `json:"modified,omitempty"`
+}
+
+// ExportFilters selects the entries of an Export
+type ExportFilters struct {
+	From       Date     `json:"from"`
+	To         Date     `json:"to"`
+	Accounts   []string `json:"accounts,omitempty"`
+	Categories []string `json:"categories,omitempty"`
+	Tags       []string `json:"tags,omitempty"`
+}
+
+// Done reports whether the server finished generating the Export, be it
+// ready or failed
+func (e *Export) Done() bool {
+	return e.Status == ExportReady || e.Status == ExportFailed
+}
+
+func (e *Export) validate() error {
+	var nilDate Date
+	var errMsg string
+
+	switch e.Format {
+	case ExportCSV, ExportXLSX, ExportPDF:
+	default:
+		errMsg = errMsg + fmt.Sprintf("invalid 'format' %q;", e.Format)
+	}
+
+	if e.Filters.From == nilDate {
+		errMsg = errMsg + "'from' field is mandatory;"
+	}
+
+	if e.Filters.To == nilDate {
+		errMsg = errMsg + "'to' field is mandatory;"
+	}
+
+	if time.Time(e.Filters.From).After(time.Time(e.Filters.To)) {
+		errMsg = errMsg + "'from' must not be after 'to';"
+	}
+
+	if errMsg != "" {
+		return errors.New(errMsg)
+	}
+
+	return nil
+}
+
+// ExportQueryParams represents a struct of parameters usable
+// to List Exports
+type ExportQueryParams struct {
+	Page    int
+	PerPage int
+}
+
+func (e *ExportQueryParams) getQueryString() string {
+	v := url.Values{}
+
+	if e.Page > 0 {
+		v.Set("page", strconv.Itoa(e.Page))
+	}
+
+	if e.PerPage > 0 {
+		v.Set("per_page", strconv.Itoa(e.PerPage))
+	}
+
+	return v.Encode()
+}
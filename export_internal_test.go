@@ -0,0 +1,30 @@
+package toshl
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExportValidate(t *testing.T) {
+	export := &Export{
+		Format: ExportCSV,
+		Filters: ExportFilters{
+			From: Date(time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC)),
+			To:   Date(time.Date(2024, time.February, 1, 0, 0, 0, 0, time.UTC)),
+		},
+	}
+
+	err := export.validate()
+	assert.EqualError(t, err, "'from' must not be after 'to';")
+
+	err = (&Export{Format: "doc"}).validate()
+	assert.EqualError(t, err, `invalid 'format' "doc";`+
+		"'from' field is mandatory;'to' field is mandatory;")
+}
+
+func TestExportGetQueryString(t *testing.T) {
+	params := &ExportQueryParams{Page: 2, PerPage: 10}
+	assert.Equal(t, "page=2&per_page=10", params.getQueryString())
+}
@@ -0,0 +1,126 @@
+package toshl_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Philanthropists/toshl-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// exportFile is not valid UTF-8, like XLSX and PDF files
+var exportFile = []byte{0x50, 0x4b, 0x03, 0x04, 0xff, 0xfe, 0x00, 0x01}
+
+func newExportServer(t *testing.T) *httptest.Server {
+	polls := 0
+
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			switch {
+			case r.Method == http.MethodPost && r.URL.Path == "/exports":
+				var export toshl.Export
+				require.NoError(t, json.NewDecoder(r.Body).Decode(&export))
+				assert.Equal(t, toshl.ExportXLSX, export.Format)
+				assert.Equal(t, []string{"3"}, export.Filters.Accounts)
+
+				w.Header().Set("Location", "/exports/7")
+				w.WriteHeader(http.StatusCreated)
+			case r.URL.Path == "/exports/7":
+				status := toshl.ExportGenerating
+				if polls++; polls == 2 {
+					status = toshl.ExportReady
+				}
+
+				fmt.Fprintf(w, `{"id": "7", "format": "xlsx", "status": %q}`,
+					status)
+			case r.URL.Path == "/exports/7/download":
+				w.Write(exportFile)
+			case r.URL.Path == "/exports/8":
+				fmt.Fprint(w, `{"id": "8", "status": "failed"}`)
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestExportFlow(t *testing.T) {
+	server := newExportServer(t)
+	client := toshl.NewClient("", &toshl.RestHTTPClient{
+		BaseURL: server.URL,
+		Client:  server.Client(),
+	})
+
+	export := &toshl.Export{
+		Format: toshl.ExportXLSX,
+		Filters: toshl.ExportFilters{
+			From:     testDate(2024, time.January, 1),
+			To:       testDate(2024, time.December, 31),
+			Accounts: []string{"3"},
+		},
+	}
+	require.NoError(t, client.CreateExport(export))
+	assert.Equal(t, "7", export.ID)
+
+	ready, err := client.WaitExport(export.ID, time.Millisecond)
+	require.NoError(t, err)
+	assert.Equal(t, toshl.ExportReady, ready.Status)
+	assert.True(t, ready.Done())
+
+	var file bytes.Buffer
+	require.NoError(t, client.DownloadExport(export.ID, &file))
+	assert.Equal(t, exportFile, file.Bytes())
+
+	_, err = client.WaitExport("8", time.Millisecond)
+	assert.ErrorIs(t, err, toshl.ErrExportFailed)
+
+	err = client.DownloadExport("404", io.Discard)
+	assert.True(t, toshl.IsNotFound(err))
+}
+
+func TestWaitExportInvalidInterval(t *testing.T) {
+	client, requests := newTestClient(t, http.StatusOK, "")
+
+	_, err := client.WaitExport("7", 0)
+	assert.NotNil(t, err)
+	assert.Empty(t, *requests)
+}
+
+func TestCreateExportInvalid(t *testing.T) {
+	client, requests := newTestClient(t, http.StatusCreated, "")
+
+	err := client.CreateExport(&toshl.Export{Format: "docx"})
+	assert.NotNil(t, err)
+	assert.Empty(t, *requests)
+}
+
+func TestDownloadRecorded(t *testing.T) {
+	server := newExportServer(t)
+
+	var cassette bytes.Buffer
+	recorder := toshl.NewRecorder(&toshl.RestHTTPClient{
+		BaseURL: server.URL,
+		Client:  server.Client(),
+	}, &cassette)
+
+	var file bytes.Buffer
+	require.NoError(t, toshl.NewClient("", recorder).DownloadExport("7", &file))
+	assert.Equal(t, exportFile, file.Bytes())
+
+	replayer, err := toshl.NewReplayer(&cassette, toshl.MatchStrict)
+	require.NoError(t, err)
+
+	var replayed bytes.Buffer
+	require.NoError(t,
+		toshl.NewClient("", replayer).DownloadExport("7", &replayed))
+	assert.Equal(t, exportFile, replayed.Bytes())
+}
@@ -0,0 +1,47 @@
+package exporter
+
+import (
+	"encoding/csv"
+	"io"
+	"strings"
+
+	"github.com/Philanthropists/toshl-go"
+)
+
+var csvHeader = []string{
+	"Date", "Account", "Category", "Tags", "Amount", "Currency",
+	"Description",
+}
+
+// WriteCSV writes entries as CSV with a header row, sorted by date. Tags
+// are separated by ", ".
+func WriteCSV(w io.Writer, entries []toshl.Entry, names *Names) error {
+	records, err := resolve(entries, names)
+	if err != nil {
+		return err
+	}
+
+	cw := csv.NewWriter(w)
+
+	if err := cw.Write(csvHeader); err != nil {
+		return err
+	}
+
+	for _, r := range records {
+		err := cw.Write([]string{
+			r.date.Format(toshl.DateFormat),
+			r.account,
+			r.category,
+			strings.Join(r.tags, ", "),
+			r.amount.String(),
+			r.currency,
+			r.description,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
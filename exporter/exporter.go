@@ -0,0 +1,225 @@
+// Package exporter writes Toshl entries to local CSV, OFX and QIF files,
+// with account, category and tag IDs resolved to their names.
+package exporter
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Philanthropists/toshl-go"
+)
+
+// Names resolves account, category and tag IDs to their names. IDs missing
+// from the maps are written as is. Balances holds the current balance of
+// accounts by ID, in their own currency, for the formats reporting it.
+type Names struct {
+	Accounts   map[string]string
+	Categories map[string]string
+	Tags       map[string]string
+	Balances   map[string]toshl.Amount
+}
+
+// LoadNames fetches every account, category and tag, deleted ones
+// included so that old entries still resolve
+func LoadNames(ctx context.Context, c *toshl.Client) (*Names, error) {
+	names := &Names{
+		Accounts:   map[string]string{},
+		Categories: map[string]string{},
+		Tags:       map[string]string{},
+		Balances:   map[string]toshl.Amount{},
+	}
+
+	for account, err := range c.AllAccounts(
+		ctx, &toshl.AccountQueryParams{IncludeDeleted: true},
+	) {
+		if err != nil {
+			return nil, err
+		}
+
+		if account.ID != nil {
+			names.Accounts[*account.ID] = account.Name
+			names.Balances[*account.ID] = account.Balance
+		}
+	}
+
+	for category, err := range c.AllCategories(
+		ctx, &toshl.CategoryQueryParams{IncludeDeleted: true},
+	) {
+		if err != nil {
+			return nil, err
+		}
+
+		names.Categories[category.ID] = category.Name
+	}
+
+	for tag, err := range c.AllTags(
+		ctx, &toshl.TagQueryParams{IncludeDeleted: true},
+	) {
+		if err != nil {
+			return nil, err
+		}
+
+		names.Tags[tag.ID] = tag.Name
+	}
+
+	return names, nil
+}
+
+func lookup(names map[string]string, id string) string {
+	if name, ok := names[id]; ok {
+		return name
+	}
+
+	return id
+}
+
+// AccountName returns the name of an account
+func (n *Names) AccountName(id string) string {
+	if n == nil {
+		return id
+	}
+
+	return lookup(n.Accounts, id)
+}
+
+// CategoryName returns the name of a category
+func (n *Names) CategoryName(id string) string {
+	if n == nil {
+		return id
+	}
+
+	return lookup(n.Categories, id)
+}
+
+// Balance returns the current balance of an account, if known
+func (n *Names) Balance(id string) (toshl.Amount, bool) {
+	if n == nil {
+		return toshl.Amount{}, false
+	}
+
+	balance, ok := n.Balances[id]
+	return balance, ok
+}
+
+// TagNames returns the names of tags
+func (n *Names) TagNames(ids []string) []string {
+	tags := make([]string, len(ids))
+	for i, id := range ids {
+		tags[i] = id
+		if n != nil {
+			tags[i] = lookup(n.Tags, id)
+		}
+	}
+
+	return tags
+}
+
+// record is an entry with its references resolved
+type record struct {
+	id          string
+	date        time.Time
+	accountID   string
+	account     string
+	category    string
+	tags        []string
+	amount      toshl.Amount
+	currency    string
+	description string
+}
+
+// resolve returns the records of entries sorted by date, transfers having
+// a record per account
+func resolve(entries []toshl.Entry, names *Names) ([]record, error) {
+	records := make([]record, 0, len(entries))
+
+	for _, entry := range entries {
+		date, err := time.Parse(toshl.DateFormat, entry.Date)
+		if err != nil {
+			return nil, fmt.Errorf("exporter: entry %s: invalid date %q",
+				stringValue(entry.Id), entry.Date)
+		}
+
+		records = append(records, record{
+			id:          stringValue(entry.Id),
+			date:        date,
+			accountID:   entry.Account,
+			account:     names.AccountName(entry.Account),
+			category:    names.CategoryName(entry.Category),
+			tags:        names.TagNames(entry.Tags),
+			amount:      entry.Amount,
+			currency:    entry.Currency.Code,
+			description: stringValue(entry.Description),
+		})
+
+		// The receiving side of a transfer is credited to its own account
+		if transaction := entry.Transaction; transaction != nil {
+			id := transaction.Id
+			if id == "" {
+				id = stringValue(entry.Id) + "-transfer"
+			}
+
+			records = append(records, record{
+				id:          id,
+				date:        date,
+				accountID:   transaction.Account,
+				account:     names.AccountName(transaction.Account),
+				tags:        names.TagNames(entry.Tags),
+				amount:      transaction.Amount.Abs(),
+				currency:    transaction.Currency.Code,
+				description: stringValue(entry.Description),
+			})
+		}
+	}
+
+	sort.SliceStable(records, func(i, j int) bool {
+		return records[i].date.Before(records[j].date)
+	})
+
+	return records, nil
+}
+
+// byAccount groups records by account ID, in order of first appearance,
+// so that accounts sharing a name are kept apart
+func byAccount(records []record) [][]record {
+	var groups [][]record
+	index := map[string]int{}
+
+	for _, r := range records {
+		i, ok := index[r.accountID]
+		if !ok {
+			i = len(groups)
+			index[r.accountID] = i
+			groups = append(groups, nil)
+		}
+
+		groups[i] = append(groups[i], r)
+	}
+
+	return groups
+}
+
+// memo returns the description of r followed by its tags
+func (r record) memo() string {
+	if len(r.tags) == 0 {
+		return r.description
+	}
+
+	tags := "#" + strings.Join(r.tags, " #")
+	if r.description == "" {
+		return tags
+	}
+
+	return r.description + " " + tags
+}
+
+// stringValue returns the value pointed to by s, or "" when s is nil
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+
+	return *s
+}
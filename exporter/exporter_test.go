@@ -0,0 +1,248 @@
+package exporter_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"strings"
+	"testing"
+
+	"github.com/Philanthropists/toshl-go"
+	"github.com/Philanthropists/toshl-go/exporter"
+	"github.com/Philanthropists/toshl-go/toshltest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ptr(s string) *string {
+	return &s
+}
+
+var testNames = &exporter.Names{
+	Accounts:   map[string]string{"1": "Checking", "2": "Cash"},
+	Categories: map[string]string{"10": "Food", "11": "Salary"},
+	Tags:       map[string]string{"20": "lunch", "21": "work"},
+	Balances:   map[string]toshl.Amount{"1": toshl.NewAmount(123456, 2)},
+}
+
+var testEntries = []toshl.Entry{
+	{
+		Id:          ptr("102"),
+		Amount:      toshl.NewAmount(250000, 2),
+		Currency:    toshl.Currency{Code: "USD"},
+		Date:        "2024-03-01",
+		Account:     "1",
+		Category:    "11",
+		Description: ptr("March salary"),
+	},
+	{
+		Id:          ptr("101"),
+		Amount:      toshl.NewAmount(-1250, 2),
+		Currency:    toshl.Currency{Code: "USD"},
+		Date:        "2024-02-27",
+		Account:     "2",
+		Category:    "10",
+		Tags:        []string{"20", "21"},
+		Description: ptr("Sandwich, \"large\""),
+	},
+	{
+		Id:       ptr("103"),
+		Amount:   toshl.NewAmount(-800, 2),
+		Currency: toshl.Currency{Code: "USD"},
+		Date:     "2024-03-02",
+		Account:  "1",
+		Category: "99",
+	},
+}
+
+func TestWriteCSV(t *testing.T) {
+	var out bytes.Buffer
+	require.NoError(t, exporter.WriteCSV(&out, testEntries, testNames))
+
+	assert.Equal(t, strings.Join([]string{
+		"Date,Account,Category,Tags,Amount,Currency,Description",
+		`2024-02-27,Cash,Food,"lunch, work",-12.50,USD,"Sandwich, ""large"""`,
+		"2024-03-01,Checking,Salary,,2500.00,USD,March salary",
+		"2024-03-02,Checking,99,,-8.00,USD,",
+		"",
+	}, "\n"), out.String())
+}
+
+func TestWriteQIF(t *testing.T) {
+	var out bytes.Buffer
+	require.NoError(t, exporter.WriteQIF(&out, testEntries, testNames))
+
+	assert.Equal(t, strings.Join([]string{
+		"!Account", "NCash", "TBank", "^", "!Type:Bank",
+		"D02/27/2024", "T-12.50", `PSandwich, "large"`, "LFood",
+		`MSandwich, "large" #lunch #work`, "^",
+		"!Account", "NChecking", "TBank", "^", "!Type:Bank",
+		"D03/01/2024", "T2500.00", "PMarch salary", "LSalary", "^",
+		"D03/02/2024", "T-8.00", "L99", "^",
+		"",
+	}, "\n"), out.String())
+}
+
+func TestWriteOFX(t *testing.T) {
+	var out bytes.Buffer
+	require.NoError(t, exporter.WriteOFX(&out, testEntries, testNames))
+
+	assert.True(t, strings.HasPrefix(out.String(), "<?xml"))
+
+	var document struct {
+		Severity   string `xml:"SIGNONMSGSRSV1>SONRS>STATUS>SEVERITY"`
+		Server     string `xml:"SIGNONMSGSRSV1>SONRS>DTSERVER"`
+		Statements []struct {
+			AccountID    string `xml:"STMTRS>BANKACCTFROM>ACCTID"`
+			Start        string `xml:"STMTRS>BANKTRANLIST>DTSTART"`
+			End          string `xml:"STMTRS>BANKTRANLIST>DTEND"`
+			Transactions []struct {
+				Type   string `xml:"TRNTYPE"`
+				Amount string `xml:"TRNAMT"`
+				ID     string `xml:"FITID"`
+				Name   string `xml:"NAME"`
+			} `xml:"STMTRS>BANKTRANLIST>STMTTRN"`
+			Balance     string `xml:"STMTRS>LEDGERBAL>BALAMT"`
+			BalanceDate string `xml:"STMTRS>LEDGERBAL>DTASOF"`
+		} `xml:"BANKMSGSRSV1>STMTTRNRS"`
+	}
+
+	// Skip the processing instructions the decoder does not expect
+	body := out.String()[strings.Index(out.String(), "<OFX>"):]
+	require.NoError(t, xml.Unmarshal([]byte(body), &document))
+
+	assert.Equal(t, "INFO", document.Severity)
+	assert.Len(t, document.Server, 14)
+	require.Len(t, document.Statements, 2)
+
+	// No balance is known for Cash, its entries are summed instead
+	cash := document.Statements[0]
+	assert.Equal(t, "-12.50", cash.Balance)
+	assert.Equal(t, "20240227", cash.BalanceDate)
+
+	checking := document.Statements[1]
+	assert.Equal(t, "Checking", checking.AccountID)
+	assert.Equal(t, "20240301", checking.Start)
+	assert.Equal(t, "20240302", checking.End)
+	require.Len(t, checking.Transactions, 2)
+	assert.Equal(t, "CREDIT", checking.Transactions[0].Type)
+	assert.Equal(t, "2500.00", checking.Transactions[0].Amount)
+	assert.Equal(t, "102", checking.Transactions[0].ID)
+	assert.Equal(t, "DEBIT", checking.Transactions[1].Type)
+	assert.Equal(t, "1234.56", checking.Balance)
+	assert.Equal(t, document.Server, checking.BalanceDate)
+}
+
+var transfer = toshl.Entry{
+	Id:          ptr("104"),
+	Amount:      toshl.NewAmount(-10000, 2),
+	Currency:    toshl.Currency{Code: "USD"},
+	Date:        "2024-03-03",
+	Account:     "1",
+	Description: ptr("Savings"),
+	Transaction: &toshl.Transaction{
+		Id:       "105",
+		Amount:   toshl.NewAmount(9000, 2),
+		Account:  "2",
+		Currency: toshl.Currency{Code: "EUR"},
+	},
+}
+
+func TestWriteTransfer(t *testing.T) {
+	var out bytes.Buffer
+	require.NoError(t, exporter.WriteCSV(
+		&out, []toshl.Entry{transfer}, testNames))
+
+	assert.Equal(t, strings.Join([]string{
+		"Date,Account,Category,Tags,Amount,Currency,Description",
+		"2024-03-03,Checking,,,-100.00,USD,Savings",
+		"2024-03-03,Cash,,,90.00,EUR,Savings",
+		"",
+	}, "\n"), out.String())
+
+	out.Reset()
+	require.NoError(t, exporter.WriteQIF(
+		&out, []toshl.Entry{transfer}, testNames))
+	assert.Contains(t, out.String(), "NCash\nTBank\n^\n!Type:Bank\n"+
+		"D03/03/2024\nT90.00\n")
+
+	out.Reset()
+	require.NoError(t, exporter.WriteOFX(
+		&out, []toshl.Entry{transfer}, testNames))
+	assert.Contains(t, out.String(), "<ACCTID>Cash</ACCTID>")
+	assert.Contains(t, out.String(), "<TRNAMT>90.00</TRNAMT>")
+	assert.Contains(t, out.String(), "<FITID>105</FITID>")
+}
+
+func TestWriteSameAccountName(t *testing.T) {
+	names := &exporter.Names{
+		Accounts: map[string]string{"1": "Cash", "2": "Cash"},
+		Balances: map[string]toshl.Amount{
+			"1": toshl.NewAmount(10, 0),
+			"2": toshl.NewAmount(20, 0),
+		},
+	}
+
+	entries := []toshl.Entry{
+		{
+			Id:       ptr("1"),
+			Amount:   toshl.NewAmount(-5, 0),
+			Currency: toshl.Currency{Code: "USD"},
+			Date:     "2024-03-01",
+			Account:  "1",
+		},
+		{
+			Id:       ptr("2"),
+			Amount:   toshl.NewAmount(-7, 0),
+			Currency: toshl.Currency{Code: "EUR"},
+			Date:     "2024-03-02",
+			Account:  "2",
+		},
+	}
+
+	var out bytes.Buffer
+	require.NoError(t, exporter.WriteOFX(&out, entries, names))
+	assert.Equal(t, 2, strings.Count(out.String(), "<ACCTID>Cash</ACCTID>"))
+	assert.Contains(t, out.String(), "<CURDEF>EUR</CURDEF>")
+	assert.Contains(t, out.String(), "<BALAMT>20</BALAMT>")
+
+	out.Reset()
+	require.NoError(t, exporter.WriteQIF(&out, entries, names))
+	assert.Equal(t, 2, strings.Count(out.String(), "NCash\n"))
+}
+
+func TestWriteInvalidDate(t *testing.T) {
+	entries := []toshl.Entry{{Id: ptr("1"), Date: "yesterday"}}
+
+	err := exporter.WriteCSV(&bytes.Buffer{}, entries, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid date")
+}
+
+func TestLoadNames(t *testing.T) {
+	server := toshltest.NewServer()
+	defer server.Close()
+
+	accountID, err := server.Add("accounts", toshl.CreateAccountParams{
+		Name:     "Checking",
+		Currency: toshl.Currency{Code: "USD"},
+	})
+	require.NoError(t, err)
+
+	categoryID, err := server.Add("categories",
+		toshl.Category{Name: "Food", Type: "expense"})
+	require.NoError(t, err)
+
+	tagID, err := server.Add("tags", toshl.Tag{Name: "lunch", Type: "expense"})
+	require.NoError(t, err)
+
+	names, err := exporter.LoadNames(context.Background(), server.NewClient())
+	require.NoError(t, err)
+
+	assert.Equal(t, "Checking", names.AccountName(accountID))
+	_, ok := names.Balance(accountID)
+	assert.True(t, ok)
+	assert.Equal(t, "Food", names.CategoryName(categoryID))
+	assert.Equal(t, []string{"lunch", "unknown"},
+		names.TagNames([]string{tagID, "unknown"}))
+}
@@ -0,0 +1,151 @@
+package exporter
+
+import (
+	"encoding/xml"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/Philanthropists/toshl-go"
+)
+
+// ofxHeader is the header of an OFX 2.2 file
+const ofxHeader = `<?xml version="1.0" encoding="UTF-8" standalone="no"?>
+<?OFX OFXHEADER="200" VERSION="220" SECURITY="NONE" OLDFILEUID="NONE" NEWFILEUID="NONE"?>
+`
+
+// ofxDate is the date format of OFX
+const ofxDate = "20060102"
+
+// ofxDateTime is the date and time format of OFX
+const ofxDateTime = "20060102150405"
+
+// ofxNameLength is the maximum length of the NAME element
+const ofxNameLength = 32
+
+type ofxDocument struct {
+	XMLName    xml.Name       `xml:"OFX"`
+	SignOn     ofxSignOn      `xml:"SIGNONMSGSRSV1>SONRS"`
+	Statements []ofxStatement `xml:"BANKMSGSRSV1>STMTTRNRS"`
+}
+
+type ofxSignOn struct {
+	StatusCode int    `xml:"STATUS>CODE"`
+	Severity   string `xml:"STATUS>SEVERITY"`
+	Server     string `xml:"DTSERVER"`
+	Language   string `xml:"LANGUAGE"`
+}
+
+type ofxStatement struct {
+	TransactionUID string           `xml:"TRNUID"`
+	StatusCode     int              `xml:"STATUS>CODE"`
+	Severity       string           `xml:"STATUS>SEVERITY"`
+	Currency       string           `xml:"STMTRS>CURDEF"`
+	BankID         string           `xml:"STMTRS>BANKACCTFROM>BANKID"`
+	AccountID      string           `xml:"STMTRS>BANKACCTFROM>ACCTID"`
+	AccountType    string           `xml:"STMTRS>BANKACCTFROM>ACCTTYPE"`
+	Start          string           `xml:"STMTRS>BANKTRANLIST>DTSTART"`
+	End            string           `xml:"STMTRS>BANKTRANLIST>DTEND"`
+	Transactions   []ofxTransaction `xml:"STMTRS>BANKTRANLIST>STMTTRN"`
+	Balance        string           `xml:"STMTRS>LEDGERBAL>BALAMT"`
+	BalanceDate    string           `xml:"STMTRS>LEDGERBAL>DTASOF"`
+}
+
+type ofxTransaction struct {
+	Type   string `xml:"TRNTYPE"`
+	Posted string `xml:"DTPOSTED"`
+	Amount string `xml:"TRNAMT"`
+	ID     string `xml:"FITID"`
+	Name   string `xml:"NAME,omitempty"`
+	Memo   string `xml:"MEMO,omitempty"`
+}
+
+// WriteOFX writes entries as an OFX 2.2 bank statement per account, the
+// account name being used as ACCTID. Entries are named after their
+// category, the memo holding their description and tags. The ledger
+// balance is the current balance of the account from names or, when
+// unknown, the sum of its entries as of the last one.
+func WriteOFX(w io.Writer, entries []toshl.Entry, names *Names) error {
+	records, err := resolve(entries, names)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC().Format(ofxDateTime)
+
+	document := ofxDocument{
+		SignOn: ofxSignOn{
+			Severity: "INFO",
+			Server:   now,
+			Language: "ENG",
+		},
+	}
+
+	for i, group := range byAccount(records) {
+		statement := ofxStatement{
+			TransactionUID: strconv.Itoa(i),
+			Severity:       "INFO",
+			Currency:       group[0].currency,
+			BankID:         "toshl",
+			AccountID:      group[0].account,
+			AccountType:    "CHECKING",
+			Start:          group[0].date.Format(ofxDate),
+			End:            group[len(group)-1].date.Format(ofxDate),
+		}
+
+		balance, ok := names.Balance(group[0].accountID)
+		if ok {
+			statement.BalanceDate = now
+		} else {
+			for _, r := range group {
+				balance = balance.Add(r.amount)
+			}
+
+			statement.BalanceDate = statement.End
+		}
+
+		statement.Balance = balance.String()
+
+		for _, r := range group {
+			transactionType := "CREDIT"
+			if r.amount.Sign() < 0 {
+				transactionType = "DEBIT"
+			}
+
+			statement.Transactions = append(statement.Transactions,
+				ofxTransaction{
+					Type:   transactionType,
+					Posted: r.date.Format(ofxDate),
+					Amount: r.amount.String(),
+					ID:     r.id,
+					Name:   truncate(r.category, ofxNameLength),
+					Memo:   r.memo(),
+				})
+		}
+
+		document.Statements = append(document.Statements, statement)
+	}
+
+	if _, err := io.WriteString(w, ofxHeader); err != nil {
+		return err
+	}
+
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+
+	if err := encoder.Encode(document); err != nil {
+		return err
+	}
+
+	_, err = io.WriteString(w, "\n")
+	return err
+}
+
+func truncate(s string, length int) string {
+	runes := []rune(s)
+	if len(runes) <= length {
+		return s
+	}
+
+	return string(runes[:length])
+}
@@ -0,0 +1,54 @@
+package exporter
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/Philanthropists/toshl-go"
+)
+
+// qifDate is the US date format expected by most QIF readers
+const qifDate = "01/02/2006"
+
+// WriteQIF writes entries as QIF, one bank account section per account.
+// Categories are written as QIF categories and tags in the memo.
+func WriteQIF(w io.Writer, entries []toshl.Entry, names *Names) error {
+	records, err := resolve(entries, names)
+	if err != nil {
+		return err
+	}
+
+	bw := bufio.NewWriter(w)
+
+	for _, group := range byAccount(records) {
+		fmt.Fprintf(bw, "!Account\nN%s\nTBank\n^\n!Type:Bank\n",
+			qifField(group[0].account))
+
+		for _, r := range group {
+			fmt.Fprintf(bw, "D%s\nT%s\n", r.date.Format(qifDate), r.amount)
+
+			if r.description != "" {
+				fmt.Fprintf(bw, "P%s\n", qifField(r.description))
+			}
+
+			if r.category != "" {
+				fmt.Fprintf(bw, "L%s\n", qifField(r.category))
+			}
+
+			if len(r.tags) > 0 {
+				fmt.Fprintf(bw, "M%s\n", qifField(r.memo()))
+			}
+
+			fmt.Fprint(bw, "^\n")
+		}
+	}
+
+	return bw.Flush()
+}
+
+// qifField keeps a value on a single line, QIF being line based
+func qifField(s string) string {
+	return strings.NewReplacer("\r\n", " ", "\n", " ", "\r", " ").Replace(s)
+}
@@ -0,0 +1,40 @@
+package forecast
+
+import (
+	"encoding/csv"
+	"io"
+)
+
+// WriteCSV writes the forecast as CSV, one row per day with the balance of
+// every account and the total, ready to be charted. Accounts are named in
+// the header row.
+func (f *Forecast) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+
+	header := []string{"Date"}
+	for _, account := range f.Accounts {
+		header = append(header, account.Name)
+	}
+
+	header = append(header, "Total")
+
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+
+	for _, day := range f.Days {
+		record := []string{day.Date.String()}
+		for _, account := range f.Accounts {
+			record = append(record, day.Balances[*account.ID].String())
+		}
+
+		record = append(record, day.Total.String())
+
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,468 @@
+// Package forecast projects the balances of Toshl accounts day by day,
+// from their current balance, the entries already scheduled, the future
+// occurrences of repeating entries and what is left of the budgets. Days
+// where an account falls below zero or below its goal are flagged.
+package forecast
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/Philanthropists/toshl-go"
+)
+
+// DefaultMonths is the length of a forecast when Options.Months is not set
+const DefaultMonths = 3
+
+// Lookback is how many months back Load looks for the latest entry of
+// repeating series, a year being the longest Repeat frequency
+const Lookback = 12
+
+// Options configures a forecast
+type Options struct {
+	// From is the first day of the forecast, today when not set. Account
+	// balances are taken as the balances at the end of that day, like
+	// the current balances Toshl reports, so only what happens after it
+	// changes them.
+	From toshl.Date
+
+	// Months is the length of the forecast, DefaultMonths when not set
+	Months int
+
+	// Currency is the currency of the total, the one of the first account
+	// when not set
+	Currency string
+
+	// Converter converts amounts between currencies at the rates of From.
+	// It is only needed when accounts, entries or budgets use different
+	// currencies.
+	Converter *toshl.Converter
+
+	// BudgetAccount is the ID of the account budget spending is charged
+	// to. When not set, budgets only lower the total.
+	BudgetAccount string
+}
+
+// Input holds what a forecast is computed from
+type Input struct {
+	Accounts []toshl.Account
+
+	// Entries are the entries dated after From, and those of the
+	// repeating series to project, the latest entry or template of each
+	Entries []toshl.Entry
+
+	// Budgets spend what is left of their Limit, less the Amount already
+	// spent and the Planned repeating entries, which are projected with
+	// the entries, evenly over the days left in their period
+	Budgets []toshl.Budget
+}
+
+// AlertKind represents why a day is flagged
+type AlertKind string
+
+const (
+	BelowZero AlertKind = "below_zero"
+	BelowGoal AlertKind = "below_goal"
+)
+
+// Alert flags an account projected below zero or, failing that, below the
+// amount of its goal
+type Alert struct {
+	Date      toshl.Date   `json:"date"`
+	Account   string       `json:"account"`
+	Kind      AlertKind    `json:"kind"`
+	Balance   toshl.Amount `json:"balance"`
+	Threshold toshl.Amount `json:"threshold"`
+}
+
+// Day holds the projected balances at the end of a day, by account ID and
+// in the currency of each account, and their total
+type Day struct {
+	Date     toshl.Date              `json:"date"`
+	Balances map[string]toshl.Amount `json:"balances"`
+	Total    toshl.Amount            `json:"total"`
+	Alerts   []Alert                 `json:"alerts,omitempty"`
+}
+
+// Forecast is a time series of projected balances, one Day per day
+type Forecast struct {
+	Currency string          `json:"currency"`
+	Accounts []toshl.Account `json:"accounts"`
+	Days     []Day           `json:"days"`
+}
+
+// Alerts returns the alerts of every day, in order
+func (f *Forecast) Alerts() []Alert {
+	var alerts []Alert
+	for _, day := range f.Days {
+		alerts = append(alerts, day.Alerts...)
+	}
+
+	return alerts
+}
+
+// Load fetches the accounts, the budgets overlapping the forecast and the
+// entries Compute needs: those dated after From, and those of the last
+// Lookback months only when they repeat
+func Load(
+	ctx context.Context, c *toshl.Client, options Options,
+) (*Input, error) {
+	from, to := window(&options)
+	input := &Input{}
+
+	for account, err := range c.AllAccounts(ctx, nil) {
+		if err != nil {
+			return nil, err
+		}
+
+		input.Accounts = append(input.Accounts, account)
+	}
+
+	for budget, err := range c.AllBudgets(ctx, nil) {
+		if err != nil {
+			return nil, err
+		}
+
+		if within(from, "", budget.To) && within(to, budget.From, "") {
+			input.Budgets = append(input.Budgets, budget)
+		}
+	}
+
+	params := &toshl.EntryQueryParams{
+		From: toshl.Date(from.AddDate(0, -Lookback, 0)),
+		To:   toshl.Date(to),
+	}
+
+	for entry, err := range c.AllEntries(ctx, params) {
+		if err != nil {
+			return nil, err
+		}
+
+		if entry.Repeat != nil || entry.Date > from.Format(toshl.DateFormat) {
+			input.Entries = append(input.Entries, entry)
+		}
+	}
+
+	return input, nil
+}
+
+// window sets the defaults of options and returns the first and last day
+// of the forecast
+func window(options *Options) (from, to time.Time) {
+	if time.Time(options.From).IsZero() {
+		options.From = toshl.Date(time.Now())
+	}
+
+	if options.Months <= 0 {
+		options.Months = DefaultMonths
+	}
+
+	from = dateOnly(time.Time(options.From))
+	options.From = toshl.Date(from)
+
+	return from, from.AddDate(0, options.Months, -1)
+}
+
+// forecaster holds the state of a forecast being computed
+type forecaster struct {
+	options  Options
+	currency string
+
+	// currencies holds the currency of each account
+	currencies map[string]string
+
+	// changes holds the balance changes of each account, by date, in the
+	// currency of the account. Changes to no account only affect the
+	// total and are in the currency of the total.
+	changes map[string]map[string]toshl.Amount
+}
+
+// Compute projects the balances of the accounts of input, deleted ones
+// excepted
+func Compute(
+	ctx context.Context, input *Input, options Options,
+) (*Forecast, error) {
+	from, to := window(&options)
+
+	f := &forecaster{
+		options:    options,
+		currency:   options.Currency,
+		currencies: map[string]string{},
+		changes:    map[string]map[string]toshl.Amount{},
+	}
+
+	forecast := &Forecast{}
+
+	for _, account := range input.Accounts {
+		if account.Deleted || account.ID == nil {
+			continue
+		}
+
+		currency := ""
+		if account.Currency != nil {
+			currency = account.Currency.Code
+		}
+
+		if f.currency == "" {
+			f.currency = currency
+		}
+
+		f.currencies[*account.ID] = currency
+		forecast.Accounts = append(forecast.Accounts, account)
+	}
+
+	forecast.Currency = f.currency
+
+	// The balances already hold what happens on the first day
+	next := from.AddDate(0, 0, 1)
+
+	if err := f.addEntries(ctx, input.Entries, next, to); err != nil {
+		return nil, err
+	}
+
+	for _, budget := range input.Budgets {
+		if err := f.addBudget(ctx, budget, next, to); err != nil {
+			return nil, err
+		}
+	}
+
+	balances := map[string]toshl.Amount{}
+	for _, account := range forecast.Accounts {
+		balances[*account.ID] = account.Balance
+	}
+
+	var unassigned toshl.Amount
+
+	for date := from; !date.After(to); date = date.AddDate(0, 0, 1) {
+		key := date.Format(toshl.DateFormat)
+		day := Day{
+			Date:     toshl.Date(date),
+			Balances: map[string]toshl.Amount{},
+		}
+
+		unassigned = unassigned.Add(f.changes[""][key])
+
+		total := unassigned
+		for _, account := range forecast.Accounts {
+			id := *account.ID
+
+			balance := balances[id].Add(f.changes[id][key])
+			balances[id] = balance
+			day.Balances[id] = balance
+
+			converted, err := f.convert(
+				ctx, balance, f.currencies[id], f.currency)
+			if err != nil {
+				return nil, err
+			}
+
+			total = total.Add(converted)
+
+			if alert, ok := check(&account, date, balance); ok {
+				day.Alerts = append(day.Alerts, alert)
+			}
+		}
+
+		day.Total = total
+		forecast.Days = append(forecast.Days, day)
+	}
+
+	return forecast, nil
+}
+
+// addEntries adds the entries dated between from and to, and the
+// projected occurrences of the repeating ones
+func (f *forecaster) addEntries(
+	ctx context.Context, entries []toshl.Entry, from, to time.Time,
+) error {
+	projected, err := toshl.ProjectEntries(
+		entries, toshl.Date(from), toshl.Date(to))
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range slices.Concat(entries, projected) {
+		if entry.Deleted || entry.IsSplit() ||
+			(entry.Repeat != nil && entry.Repeat.IsTemplate) {
+			continue
+		}
+
+		date, err := time.Parse(toshl.DateFormat, entry.Date)
+		if err != nil {
+			return err
+		}
+
+		if date.Before(from) || date.After(to) {
+			continue
+		}
+
+		err = f.add(ctx, entry.Account, entry.Date,
+			entry.Amount, entry.Currency.Code)
+		if err != nil {
+			return err
+		}
+
+		if entry.Transaction != nil {
+			err = f.add(ctx, entry.Transaction.Account, entry.Date,
+				entry.Transaction.Amount.Abs(),
+				entry.Transaction.Currency.Code)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// addBudget spreads what is left of budget over the days left in its
+// period, the last day taking the rounding remainder
+func (f *forecaster) addBudget(
+	ctx context.Context, budget toshl.Budget, from, to time.Time,
+) error {
+	if budget.Deleted {
+		return nil
+	}
+
+	left := budget.Limit.Sub(budget.Amount).Sub(budget.Planned)
+	if left.Sign() <= 0 {
+		return nil
+	}
+
+	start, err := time.Parse(toshl.DateFormat, budget.From)
+	if err != nil {
+		return fmt.Errorf("forecast: budget %s: %w", budget.ID, err)
+	}
+
+	end, err := time.Parse(toshl.DateFormat, budget.To)
+	if err != nil {
+		return fmt.Errorf("forecast: budget %s: %w", budget.ID, err)
+	}
+
+	if end.Before(from) {
+		return nil
+	}
+
+	if start.Before(from) {
+		start = from
+	}
+
+	days := int(end.Sub(start).Hours()/24) + 1
+	perDay := left.Div(toshl.NewAmount(int64(days), 0),
+		toshl.CurrencyPrecision(budget.Currency.Code))
+	last := left.Sub(perDay.Mul(toshl.NewAmount(int64(days-1), 0)))
+
+	for n := range days {
+		date := start.AddDate(0, 0, n)
+		if date.After(to) {
+			break
+		}
+
+		spent := perDay
+		if n == days-1 {
+			spent = last
+		}
+
+		err := f.add(ctx, f.options.BudgetAccount, date.Format(toshl.DateFormat),
+			spent.Neg(), budget.Currency.Code)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// add records a change of the balance of account on date, converted to
+// the currency of the account. Changes to accounts that are not forecast
+// only affect the total.
+func (f *forecaster) add(
+	ctx context.Context, account, date string, amount toshl.Amount,
+	currency string,
+) error {
+	target, ok := f.currencies[account]
+	if !ok {
+		account, target = "", f.currency
+	}
+
+	amount, err := f.convert(ctx, amount, currency, target)
+	if err != nil {
+		return err
+	}
+
+	if f.changes[account] == nil {
+		f.changes[account] = map[string]toshl.Amount{}
+	}
+
+	f.changes[account][date] = f.changes[account][date].Add(amount)
+
+	return nil
+}
+
+// convert converts amount at the rates of the first day, amounts without
+// a currency being taken as already in the target one
+func (f *forecaster) convert(
+	ctx context.Context, amount toshl.Amount, from, to string,
+) (toshl.Amount, error) {
+	if from == to || from == "" || to == "" {
+		return amount, nil
+	}
+
+	if f.options.Converter == nil {
+		return toshl.Amount{}, fmt.Errorf(
+			"forecast: converting %s to %s needs a Converter", from, to)
+	}
+
+	return f.options.Converter.Convert(ctx, amount, from, to, f.options.From)
+}
+
+// check flags account when balance is below zero or below its goal
+func check(
+	account *toshl.Account, date time.Time, balance toshl.Amount,
+) (Alert, bool) {
+	alert := Alert{
+		Date:    toshl.Date(date),
+		Account: *account.ID,
+		Balance: balance,
+	}
+
+	if balance.Sign() < 0 {
+		alert.Kind = BelowZero
+		return alert, true
+	}
+
+	goal := account.Goal
+	if goal == nil || !within(date, goal.Start, goal.End) {
+		return Alert{}, false
+	}
+
+	if balance.Cmp(goal.Amount) < 0 {
+		alert.Kind = BelowGoal
+		alert.Threshold = goal.Amount
+		return alert, true
+	}
+
+	return Alert{}, false
+}
+
+// within reports whether date is between start and end, either being
+// unbounded when empty or invalid
+func within(date time.Time, start, end string) bool {
+	if t, err := time.Parse(toshl.DateFormat, start); err == nil &&
+		date.Before(t) {
+		return false
+	}
+
+	if t, err := time.Parse(toshl.DateFormat, end); err == nil &&
+		date.After(t) {
+		return false
+	}
+
+	return true
+}
+
+func dateOnly(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,272 @@
+package forecast_test
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Philanthropists/toshl-go"
+	"github.com/Philanthropists/toshl-go/forecast"
+	"github.com/Philanthropists/toshl-go/toshltest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ptr(s string) *string {
+	return &s
+}
+
+func date(year int, month time.Month, day int) toshl.Date {
+	return toshl.Date(time.Date(year, month, day, 0, 0, 0, 0, time.UTC))
+}
+
+var (
+	march = date(2024, time.March, 1)
+
+	checking = toshl.Account{
+		ID:       ptr("1"),
+		Name:     "Checking",
+		Balance:  toshl.NewAmount(100, 0),
+		Currency: &toshl.Currency{Code: "EUR"},
+		Goal:     &toshl.Goal{Amount: toshl.NewAmount(150, 0)},
+	}
+
+	savings = toshl.Account{
+		ID:       ptr("2"),
+		Name:     "Savings",
+		Balance:  toshl.NewAmount(1000, 0),
+		Currency: &toshl.Currency{Code: "USD"},
+	}
+
+	rent = &toshl.Repeat{
+		Id:        "rent",
+		Frequency: toshl.Monthly,
+		Start:     date(2024, time.January, 5),
+	}
+)
+
+func testInput() *forecast.Input {
+	return &forecast.Input{
+		Accounts: []toshl.Account{checking, savings},
+		Entries: []toshl.Entry{
+			{
+				Amount:   toshl.NewAmount(-150, 0),
+				Currency: toshl.Currency{Code: "EUR"},
+				Date:     "2024-02-05",
+				Account:  "1",
+				Repeat:   rent,
+			},
+			{
+				Amount:   toshl.NewAmount(-999, 0),
+				Currency: toshl.Currency{Code: "EUR"},
+				Date:     "2024-02-28",
+				Account:  "1",
+			},
+			{
+				// Already part of the balance of the first day
+				Amount:   toshl.NewAmount(-45, 0),
+				Currency: toshl.Currency{Code: "EUR"},
+				Date:     "2024-03-01",
+				Account:  "1",
+			},
+			{
+				Amount:   toshl.NewAmount(-20, 0),
+				Currency: toshl.Currency{Code: "EUR"},
+				Date:     "2024-03-10",
+				Account:  "1",
+				Transaction: &toshl.Transaction{
+					Amount:   toshl.NewAmount(40, 0),
+					Account:  "2",
+					Currency: toshl.Currency{Code: "USD"},
+				},
+			},
+			{
+				Amount:   toshl.NewAmount(200, 0),
+				Currency: toshl.Currency{Code: "EUR"},
+				Date:     "2024-03-25",
+				Account:  "1",
+			},
+		},
+		Budgets: []toshl.Budget{{
+			ID:       "10",
+			Limit:    toshl.NewAmount(190, 0),
+			Amount:   toshl.NewAmount(10, 0),
+			Planned:  toshl.NewAmount(150, 0),
+			Currency: toshl.Currency{Code: "EUR"},
+			From:     "2024-03-01",
+			To:       "2024-03-31",
+		}},
+	}
+}
+
+func testConverter() *toshl.Converter {
+	converter := toshl.NewConverter(nil)
+	converter.AddRates(&toshl.ExchangeRates{
+		Date:  march,
+		Base:  "EUR",
+		Rates: map[string]toshl.Amount{"USD": toshl.NewAmount(2, 0)},
+	})
+
+	return converter
+}
+
+func TestCompute(t *testing.T) {
+	f, err := forecast.Compute(context.Background(), testInput(),
+		forecast.Options{
+			From:      march,
+			Months:    1,
+			Converter: testConverter(),
+		})
+	require.NoError(t, err)
+
+	assert.Equal(t, "EUR", f.Currency)
+	require.Len(t, f.Days, 31)
+
+	for _, test := range []struct {
+		day      int
+		checking string
+		savings  string
+		total    string
+	}{
+		{1, "100", "1000", "600.00"},
+		{5, "-50", "1000", "446.00"},
+		{10, "-70", "1040", "441.00"},
+		{25, "130", "1040", "626.00"},
+		{31, "130", "1040", "620.00"},
+	} {
+		day := f.Days[test.day-1]
+		assert.Equal(t, date(2024, time.March, test.day), day.Date)
+		assert.Equal(t, test.checking, day.Balances["1"].String())
+		assert.Equal(t, test.savings, day.Balances["2"].String())
+		assert.Equal(t, test.total, day.Total.String(), "day %d", test.day)
+	}
+
+	alerts := f.Alerts()
+	require.Len(t, alerts, 31)
+
+	assert.Equal(t, forecast.Alert{
+		Date:      march,
+		Account:   "1",
+		Kind:      forecast.BelowGoal,
+		Balance:   toshl.NewAmount(100, 0),
+		Threshold: toshl.NewAmount(150, 0),
+	}, alerts[0])
+	assert.Equal(t, forecast.BelowZero, alerts[4].Kind)
+	assert.Equal(t, forecast.BelowZero, alerts[23].Kind)
+	assert.Equal(t, forecast.BelowGoal, alerts[24].Kind)
+}
+
+func TestComputeBudgetAccount(t *testing.T) {
+	f, err := forecast.Compute(context.Background(), testInput(),
+		forecast.Options{
+			From:          march,
+			Months:        1,
+			Converter:     testConverter(),
+			BudgetAccount: "1",
+		})
+	require.NoError(t, err)
+
+	last := f.Days[len(f.Days)-1]
+	assert.Equal(t, "100.00", last.Balances["1"].String())
+	assert.Equal(t, "620.00", last.Total.String())
+}
+
+func TestComputeBudgetSpent(t *testing.T) {
+	input := testInput()
+	input.Budgets[0].Amount = toshl.NewAmount(50, 0)
+
+	f, err := forecast.Compute(context.Background(), input,
+		forecast.Options{
+			From:      march,
+			Months:    1,
+			Converter: testConverter(),
+		})
+	require.NoError(t, err)
+
+	// Limit less spent and planned is negative, nothing is left to spend
+	last := f.Days[len(f.Days)-1]
+	assert.Equal(t, "650.00", last.Total.String())
+}
+
+func TestComputeNeedsConverter(t *testing.T) {
+	_, err := forecast.Compute(context.Background(), testInput(),
+		forecast.Options{From: march})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "needs a Converter")
+}
+
+func TestWriteCSV(t *testing.T) {
+	f, err := forecast.Compute(context.Background(), testInput(),
+		forecast.Options{
+			From:      march,
+			Months:    1,
+			Converter: testConverter(),
+		})
+	require.NoError(t, err)
+
+	var out bytes.Buffer
+	require.NoError(t, f.WriteCSV(&out))
+
+	lines := strings.Split(out.String(), "\n")
+	require.Len(t, lines, 33)
+	assert.Equal(t, "Date,Checking,Savings,Total", lines[0])
+	assert.Equal(t, "2024-03-05,-50,1000,446.00", lines[5])
+}
+
+func TestLoad(t *testing.T) {
+	server := toshltest.NewServer()
+	defer server.Close()
+
+	accountID, err := server.Add("accounts", toshl.CreateAccountParams{
+		Name:     "Checking",
+		Currency: toshl.Currency{Code: "EUR"},
+	})
+	require.NoError(t, err)
+
+	for _, entry := range []toshl.Entry{
+		{Date: "2023-12-05", Repeat: rent},
+		{Date: "2024-02-05", Repeat: rent},
+		{Date: "2024-02-28"},
+		{Date: "2024-03-01"},
+		{Date: "2024-03-25"},
+		{Date: "2024-07-01"},
+	} {
+		entry.Amount = toshl.NewAmount(-10, 0)
+		entry.Currency = toshl.Currency{Code: "EUR"}
+		entry.Account = accountID
+
+		_, err := server.Add("entries", entry)
+		require.NoError(t, err)
+	}
+
+	for _, period := range [][2]string{
+		{"2024-02-01", "2024-02-29"},
+		{"2024-03-01", "2024-03-31"},
+	} {
+		_, err := server.Add("budgets", toshl.Budget{
+			Name:  "Food",
+			Limit: toshl.NewAmount(300, 0),
+			From:  period[0],
+			To:    period[1],
+		})
+		require.NoError(t, err)
+	}
+
+	input, err := forecast.Load(context.Background(), server.NewClient(),
+		forecast.Options{From: march, Months: 2})
+	require.NoError(t, err)
+
+	assert.Len(t, input.Accounts, 1)
+	require.Len(t, input.Budgets, 1)
+	assert.Equal(t, "2024-03-01", input.Budgets[0].From)
+
+	var dates []string
+	for _, entry := range input.Entries {
+		dates = append(dates, entry.Date)
+	}
+
+	assert.ElementsMatch(t,
+		[]string{"2023-12-05", "2024-02-05", "2024-03-25"}, dates)
+}
@@ -1,7 +1,12 @@
 package toshl
 
 import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -11,3 +16,78 @@ func TestRestHTTPClientParseID(t *testing.T) {
 	id, _ := c.parseIDFromLocationHeader("https://api.toshl.com/accounts/42")
 	assert.Equal(t, id, "42")
 }
+
+func TestRestHTTPClientGetMultipleContextCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	requests := 0
+
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			cancel()
+			w.Header().Set("Link",
+				`<https://api.toshl.com/entries?page=2>; rel="next"`)
+			w.Write([]byte(`[]`))
+		}))
+	defer server.Close()
+
+	c := &RestHTTPClient{BaseURL: server.URL, Client: server.Client()}
+
+	_, err := c.GetMultipleContext(ctx, "entries", "")
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 1, requests)
+}
+
+func TestRestHTTPClientGetContextCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	c := &RestHTTPClient{BaseURL: "http://127.0.0.1:0", Client: &http.Client{}}
+
+	_, err := c.GetContext(ctx, "accounts", "")
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestRestHTTPClientPostWithoutLocation(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNoContent)
+		}))
+	defer server.Close()
+
+	c := &RestHTTPClient{BaseURL: server.URL, Client: server.Client()}
+
+	id, err := c.Post("budgets/reorder", `{"order":["1"]}`)
+	assert.Nil(t, err)
+	assert.Equal(t, "", id)
+}
+
+func TestRestHTTPClientDownloadRetried(t *testing.T) {
+	requests := 0
+
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			if requests++; requests == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+
+			w.Write([]byte("date,amount\n"))
+		}))
+	defer server.Close()
+
+	c := &RestHTTPClient{
+		BaseURL: server.URL,
+		Client:  server.Client(),
+		RetryPolicy: &RetryPolicy{
+			MaxAttempts:    2,
+			InitialBackoff: time.Millisecond,
+		},
+	}
+
+	var file bytes.Buffer
+	err := c.Download("exports/7/download", "", &file)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, requests)
+	assert.Equal(t, "date,amount\n", file.String())
+}
@@ -0,0 +1,144 @@
+package importer
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/Philanthropists/toshl-go"
+)
+
+// CSVMapping maps the columns of a CSV statement, named by its header row,
+// to the fields of a Row. Optional columns are left empty.
+type CSVMapping struct {
+	Date        string
+	Amount      string
+	Description string
+
+	// Account, Category and Currency are optional
+	Account  string
+	Category string
+	Currency string
+
+	// DateLayout is the time layout of the dates, "2006-01-02" by default
+	DateLayout string
+
+	// Comma is the field delimiter, ',' by default
+	Comma rune
+
+	// DecimalComma is set for amounts like "1.234,56"
+	DecimalComma bool
+}
+
+// ParseCSV parses a CSV statement having a header row. Row.Line is the
+// line number of the record in the file.
+func ParseCSV(r io.Reader, mapping CSVMapping) ([]Row, error) {
+	reader := csv.NewReader(r)
+	if mapping.Comma != 0 {
+		reader.Comma = mapping.Comma
+	}
+
+	layout := mapping.DateLayout
+	if layout == "" {
+		layout = toshl.DateFormat
+	}
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("importer: reading CSV header: %w", err)
+	}
+
+	columns := map[string]int{}
+	for i, name := range header {
+		columns[strings.TrimSpace(name)] = i
+	}
+
+	column := func(name string, required bool) (int, error) {
+		if name == "" && !required {
+			return -1, nil
+		}
+
+		i, ok := columns[name]
+		if !ok {
+			return 0, fmt.Errorf("importer: missing CSV column %q", name)
+		}
+
+		return i, nil
+	}
+
+	var indexes [6]int
+	for i, c := range []struct {
+		name     string
+		required bool
+	}{
+		{mapping.Date, true},
+		{mapping.Amount, true},
+		{mapping.Description, false},
+		{mapping.Account, false},
+		{mapping.Category, false},
+		{mapping.Currency, false},
+	} {
+		if indexes[i], err = column(c.name, c.required); err != nil {
+			return nil, err
+		}
+	}
+
+	var rows []Row
+
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("importer: %w", err)
+		}
+
+		line, _ := reader.FieldPos(0)
+
+		field := func(i int) string {
+			if i < 0 || i >= len(record) {
+				return ""
+			}
+
+			return strings.TrimSpace(record[i])
+		}
+
+		date, err := time.Parse(layout, field(indexes[0]))
+		if err != nil {
+			return nil, fmt.Errorf("importer: line %d: invalid date %q",
+				line, field(indexes[0]))
+		}
+
+		amount, err := parseAmount(field(indexes[1]), mapping.DecimalComma)
+		if err != nil {
+			return nil, fmt.Errorf("importer: line %d: %w", line, err)
+		}
+
+		rows = append(rows, Row{
+			Line:        line,
+			Date:        toshl.Date(date),
+			Amount:      amount,
+			Description: field(indexes[2]),
+			Account:     field(indexes[3]),
+			Category:    field(indexes[4]),
+			Currency:    field(indexes[5]),
+		})
+	}
+
+	return rows, nil
+}
+
+// parseAmount parses an amount having thousands separators
+func parseAmount(s string, decimalComma bool) (toshl.Amount, error) {
+	if decimalComma {
+		s = strings.ReplaceAll(s, ".", "")
+		s = strings.ReplaceAll(s, ",", ".")
+	} else {
+		s = strings.ReplaceAll(s, ",", "")
+	}
+
+	return toshl.ParseAmount(strings.ReplaceAll(s, " ", ""))
+}
@@ -0,0 +1,324 @@
+// Package importer imports bank statements into Toshl entries. Statements
+// are parsed from CSV or OFX files into rows, which are mapped to accounts
+// and categories, checked against the existing entries to skip duplicates,
+// and created concurrently.
+package importer
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Philanthropists/toshl-go"
+)
+
+// DefaultConcurrency is the number of entries created at the same time
+// when Options.Concurrency is not set
+const DefaultConcurrency = 4
+
+// Row is a single transaction of a statement
+type Row struct {
+	// Line locates the row in the statement, for reports
+	Line int
+
+	Date        toshl.Date
+	Amount      toshl.Amount
+	Description string
+
+	// Account, Category and Currency are empty when the statement does not
+	// have them, Options providing the defaults
+	Account  string
+	Category string
+	Currency string
+}
+
+// Options configures an Importer
+type Options struct {
+	// Account is the ID of the account of rows without an account
+	Account string
+
+	// Category is the ID of the category of rows without a category
+	Category string
+
+	// Currency is the currency code of rows without a currency
+	Currency string
+
+	// Accounts and Categories map the names found in the statement to
+	// IDs. Names missing from them are taken as IDs.
+	Accounts   map[string]string
+	Categories map[string]string
+
+	// DryRun reports what would be imported without creating anything
+	DryRun bool
+
+	// Concurrency bounds the number of entries created at the same time,
+	// DefaultConcurrency when not set
+	Concurrency int
+}
+
+// Status represents the outcome of importing a Row
+type Status string
+
+const (
+	StatusCreated     Status = "created"
+	StatusWouldCreate Status = "would_create"
+	StatusDuplicate   Status = "duplicate"
+	StatusFailed      Status = "failed"
+)
+
+// Result is the outcome of importing a Row
+type Result struct {
+	Row    Row
+	Status Status
+
+	// EntryID is the ID of the created entry
+	EntryID string
+
+	// Err is the reason of a failure
+	Err error
+}
+
+// Report lists the Result of every Row, in the order of the rows
+type Report struct {
+	Results []Result
+}
+
+// Count returns the number of rows having status
+func (r *Report) Count(status Status) int {
+	count := 0
+	for _, result := range r.Results {
+		if result.Status == status {
+			count++
+		}
+	}
+
+	return count
+}
+
+// Importer creates entries from statement rows
+type Importer struct {
+	client  *toshl.Client
+	options Options
+}
+
+// New returns an Importer creating entries with client
+func New(client *toshl.Client, options Options) *Importer {
+	if options.Concurrency <= 0 {
+		options.Concurrency = DefaultConcurrency
+	}
+
+	return &Importer{client: client, options: options}
+}
+
+// errNoAccount is reported for rows without an account when no default
+// account is set
+var errNoAccount = errors.New("importer: no account")
+
+// Import imports rows, skipping those matching an existing entry of the
+// same date, amount and description. Each existing entry matches a single
+// row, so identical rows beyond the existing entries are created. Failures
+// to create an entry are reported per row; the error is only set when the
+// existing entries cannot be listed.
+func (i *Importer) Import(ctx context.Context, rows []Row) (*Report, error) {
+	report := &Report{Results: make([]Result, len(rows))}
+	entries := make([]*toshl.Entry, len(rows))
+
+	for n, row := range rows {
+		report.Results[n] = Result{Row: row}
+
+		entry, err := i.entry(row)
+		if err != nil {
+			report.Results[n].Status = StatusFailed
+			report.Results[n].Err = err
+			continue
+		}
+
+		entries[n] = entry
+	}
+
+	existing, err := i.fingerprints(ctx, entries)
+	if err != nil {
+		return nil, err
+	}
+
+	var pending []int
+
+	for n, entry := range entries {
+		if entry == nil {
+			continue
+		}
+
+		key := fingerprint(entry)
+		if existing[key] > 0 {
+			existing[key]--
+			report.Results[n].Status = StatusDuplicate
+			continue
+		}
+
+		if i.options.DryRun {
+			report.Results[n].Status = StatusWouldCreate
+			continue
+		}
+
+		pending = append(pending, n)
+	}
+
+	i.create(ctx, entries, pending, report)
+
+	return report, nil
+}
+
+// entry maps row to the entry to create
+func (i *Importer) entry(row Row) (*toshl.Entry, error) {
+	account := i.options.Account
+	if row.Account != "" {
+		account = lookup(i.options.Accounts, row.Account)
+	}
+
+	if account == "" {
+		return nil, errNoAccount
+	}
+
+	category := i.options.Category
+	if row.Category != "" {
+		category = lookup(i.options.Categories, row.Category)
+	}
+
+	currency := i.options.Currency
+	if row.Currency != "" {
+		currency = row.Currency
+	}
+
+	description := row.Description
+
+	return &toshl.Entry{
+		Amount:      row.Amount,
+		Currency:    toshl.Currency{Code: currency},
+		Date:        row.Date.String(),
+		Description: &description,
+		Account:     account,
+		Category:    category,
+	}, nil
+}
+
+func lookup(ids map[string]string, name string) string {
+	if id, ok := ids[name]; ok {
+		return id
+	}
+
+	return name
+}
+
+// fingerprints counts the existing entries of the accounts and date range
+// of entries by fingerprint
+func (i *Importer) fingerprints(
+	ctx context.Context, entries []*toshl.Entry,
+) (map[string]int, error) {
+	counts := map[string]int{}
+
+	var from, to string
+	accounts := map[string]bool{}
+
+	for _, entry := range entries {
+		if entry == nil {
+			continue
+		}
+
+		if from == "" || entry.Date < from {
+			from = entry.Date
+		}
+
+		if to == "" || entry.Date > to {
+			to = entry.Date
+		}
+
+		accounts[entry.Account] = true
+	}
+
+	if len(accounts) == 0 {
+		return counts, nil
+	}
+
+	params := &toshl.EntryQueryParams{}
+	for account := range accounts {
+		params.Accounts = append(params.Accounts, account)
+	}
+
+	// Dates were formatted from toshl.Date values, so they parse
+	fromDate, _ := time.Parse(toshl.DateFormat, from)
+	toDate, _ := time.Parse(toshl.DateFormat, to)
+	params.From, params.To = toshl.Date(fromDate), toshl.Date(toDate)
+
+	for entry, err := range i.client.AllEntries(ctx, params) {
+		if err != nil {
+			return nil, err
+		}
+
+		counts[fingerprint(&entry)]++
+	}
+
+	return counts, nil
+}
+
+// fingerprint identifies an entry by its date, amount and description,
+// ignoring the scale of the amount and the case and spacing of the
+// description
+func fingerprint(entry *toshl.Entry) string {
+	amount := entry.Amount.String()
+	if strings.Contains(amount, ".") {
+		amount = strings.TrimRight(strings.TrimRight(amount, "0"), ".")
+	}
+
+	if amount == "-0" {
+		amount = "0"
+	}
+
+	description := ""
+	if entry.Description != nil {
+		description = strings.Join(
+			strings.Fields(strings.ToLower(*entry.Description)), " ")
+	}
+
+	return entry.Date + "|" + amount + "|" + description
+}
+
+// create creates the entries at the pending indexes, at most
+// Options.Concurrency at the same time
+func (i *Importer) create(
+	ctx context.Context, entries []*toshl.Entry, pending []int,
+	report *Report,
+) {
+	var wg sync.WaitGroup
+	semaphore := make(chan struct{}, i.options.Concurrency)
+
+	for _, n := range pending {
+		wg.Add(1)
+		semaphore <- struct{}{}
+
+		go func(n int) {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+
+			result := &report.Results[n]
+
+			if err := ctx.Err(); err != nil {
+				result.Status = StatusFailed
+				result.Err = err
+				return
+			}
+
+			if err := i.client.CreateEntryContext(ctx, entries[n]); err != nil {
+				result.Status = StatusFailed
+				result.Err = err
+				return
+			}
+
+			result.Status = StatusCreated
+			result.EntryID = *entries[n].Id
+		}(n)
+	}
+
+	wg.Wait()
+}
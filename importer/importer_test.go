@@ -0,0 +1,162 @@
+package importer_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Philanthropists/toshl-go"
+	"github.com/Philanthropists/toshl-go/importer"
+	"github.com/Philanthropists/toshl-go/toshltest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const statementCSV = `Booking date;Text;Amount;Account
+05.03.2024;Coffee  shop;-3,50;checking
+05.03.2024;Coffee shop;-3,50;checking
+06.03.2024;Salary;1.250,00;checking
+07.03.2024;Cash withdrawal;-50,00;
+`
+
+var statementMapping = importer.CSVMapping{
+	Date:         "Booking date",
+	Amount:       "Amount",
+	Description:  "Text",
+	Account:      "Account",
+	DateLayout:   "02.01.2006",
+	Comma:        ';',
+	DecimalComma: true,
+}
+
+func TestParseCSV(t *testing.T) {
+	rows, err := importer.ParseCSV(
+		strings.NewReader(statementCSV), statementMapping)
+	require.NoError(t, err)
+	require.Len(t, rows, 4)
+
+	assert.Equal(t, 2, rows[0].Line)
+	assert.Equal(t, "2024-03-05", rows[0].Date.String())
+	assert.Equal(t, "-3.50", rows[0].Amount.String())
+	assert.Equal(t, "Coffee  shop", rows[0].Description)
+	assert.Equal(t, "checking", rows[0].Account)
+	assert.Equal(t, "1250.00", rows[2].Amount.String())
+	assert.Equal(t, "", rows[3].Account)
+}
+
+func TestParseCSVMissingColumn(t *testing.T) {
+	_, err := importer.ParseCSV(strings.NewReader("Date,Value\n"),
+		importer.CSVMapping{Date: "Date", Amount: "Amount"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `"Amount"`)
+}
+
+func TestParseOFX(t *testing.T) {
+	for name, statement := range map[string]string{
+		"sgml": `OFXHEADER:100
+DATA:OFXSGML
+
+<OFX><BANKMSGSRSV1><STMTTRNRS><STMTRS>
+<CURDEF>EUR
+<BANKACCTFROM><ACCTID>12345</BANKACCTFROM>
+<BANKTRANLIST>
+<STMTTRN><TRNTYPE>DEBIT<DTPOSTED>20240305120000.000[-5:EST]<TRNAMT>-3.50<NAME>Coffee &amp; cake
+<STMTTRN><TRNTYPE>CREDIT<DTPOSTED>20240306<TRNAMT>1250.00<MEMO>Salary
+</BANKTRANLIST></STMTRS></STMTTRNRS></BANKMSGSRSV1></OFX>`,
+		"xml": `<?xml version="1.0"?>
+<OFX><BANKMSGSRSV1><STMTTRNRS><STMTRS>
+<CURDEF>EUR</CURDEF>
+<BANKACCTFROM><ACCTID>12345</ACCTID></BANKACCTFROM>
+<BANKTRANLIST>
+<STMTTRN><DTPOSTED>20240305</DTPOSTED><TRNAMT>-3.50</TRNAMT><NAME>Coffee &amp; cake</NAME></STMTTRN>
+<STMTTRN><DTPOSTED>20240306</DTPOSTED><TRNAMT>1250.00</TRNAMT><MEMO>Salary</MEMO></STMTTRN>
+</BANKTRANLIST></STMTRS></STMTTRNRS></BANKMSGSRSV1></OFX>`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			rows, err := importer.ParseOFX(strings.NewReader(statement))
+			require.NoError(t, err)
+			require.Len(t, rows, 2)
+
+			assert.Equal(t, importer.Row{
+				Line:        1,
+				Date:        toshl.Date(time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC)),
+				Amount:      toshl.NewAmount(-350, 2),
+				Description: "Coffee & cake",
+				Account:     "12345",
+				Currency:    "EUR",
+			}, rows[0])
+			assert.Equal(t, "Salary", rows[1].Description)
+			assert.Equal(t, "2024-03-06", rows[1].Date.String())
+		})
+	}
+}
+
+func TestParseOFXMissingAmount(t *testing.T) {
+	_, err := importer.ParseOFX(strings.NewReader(`<OFX>
+<STMTTRN><DTPOSTED>20240305</DTPOSTED><NAME>Coffee</NAME></STMTTRN>
+</OFX>`))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "missing TRNAMT")
+}
+
+func newImport(
+	t *testing.T, dryRun bool,
+) (*toshltest.Server, *importer.Report) {
+	server := toshltest.NewServer()
+	t.Cleanup(server.Close)
+
+	// An entry imported previously, with a different amount scale
+	description := "Coffee shop"
+	_, err := server.Add("entries", toshl.Entry{
+		Amount:      toshl.NewAmount(-35, 1),
+		Currency:    toshl.Currency{Code: "EUR"},
+		Date:        "2024-03-05",
+		Description: &description,
+		Account:     "1",
+	})
+	require.NoError(t, err)
+
+	rows, err := importer.ParseCSV(
+		strings.NewReader(statementCSV), statementMapping)
+	require.NoError(t, err)
+
+	report, err := importer.New(server.NewClient(), importer.Options{
+		Currency:    "EUR",
+		Accounts:    map[string]string{"checking": "1"},
+		DryRun:      dryRun,
+		Concurrency: 2,
+	}).Import(context.Background(), rows)
+	require.NoError(t, err)
+
+	return server, report
+}
+
+func TestImport(t *testing.T) {
+	server, report := newImport(t, false)
+
+	var statuses []importer.Status
+	for _, result := range report.Results {
+		statuses = append(statuses, result.Status)
+	}
+
+	assert.Equal(t, []importer.Status{
+		importer.StatusDuplicate,
+		importer.StatusCreated,
+		importer.StatusCreated,
+		importer.StatusFailed,
+	}, statuses)
+
+	assert.NotEmpty(t, report.Results[1].EntryID)
+	assert.Error(t, report.Results[3].Err)
+	assert.Equal(t, 3, server.Count("entries"))
+}
+
+func TestImportDryRun(t *testing.T) {
+	server, report := newImport(t, true)
+
+	assert.Equal(t, 1, report.Count(importer.StatusDuplicate))
+	assert.Equal(t, 2, report.Count(importer.StatusWouldCreate))
+	assert.Equal(t, 1, report.Count(importer.StatusFailed))
+	assert.Equal(t, 1, server.Count("entries"))
+}
@@ -0,0 +1,141 @@
+package importer
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/Philanthropists/toshl-go"
+)
+
+// ofxElement matches an OFX tag and the text following it, which works
+// for both SGML (OFX 1.x) files, whose elements are not closed, and XML
+// (OFX 2.x) files
+var ofxElement = regexp.MustCompile(`<(/?)([A-Za-z0-9.]+)>([^<]*)`)
+
+// ParseOFX parses the bank and credit card transactions of an OFX
+// statement. Row.Account is the ACCTID of the statement, Row.Description
+// the NAME of the transaction, or its MEMO when it has no name, and
+// Row.Line the ordinal of the transaction in the statement, starting at 1.
+func ParseOFX(r io.Reader) ([]Row, error) {
+	bs, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []Row
+	var account, currency string
+	var row *Row
+	var name, memo string
+	var hasAmount bool
+
+	flush := func() error {
+		if row == nil {
+			return nil
+		}
+
+		row.Description = name
+		if row.Description == "" {
+			row.Description = memo
+		}
+
+		if time.Time(row.Date).IsZero() {
+			return fmt.Errorf("importer: transaction %d: missing DTPOSTED",
+				row.Line)
+		}
+
+		if !hasAmount {
+			return fmt.Errorf("importer: transaction %d: missing TRNAMT",
+				row.Line)
+		}
+
+		rows = append(rows, *row)
+		row = nil
+
+		return nil
+	}
+
+	count := 0
+
+	for _, match := range ofxElement.FindAllStringSubmatch(string(bs), -1) {
+		closing, tag := match[1] == "/", strings.ToUpper(match[2])
+		value := html.UnescapeString(strings.TrimSpace(match[3]))
+
+		if tag == "STMTTRN" {
+			if err := flush(); err != nil {
+				return nil, err
+			}
+
+			if !closing {
+				count++
+				row = &Row{Line: count, Account: account, Currency: currency}
+				name, memo, hasAmount = "", "", false
+			}
+
+			continue
+		}
+
+		if closing {
+			continue
+		}
+
+		switch tag {
+		case "ACCTID":
+			account = value
+		case "CURDEF":
+			currency = value
+		}
+
+		if row == nil {
+			continue
+		}
+
+		switch tag {
+		case "DTPOSTED":
+			date, err := parseOFXDate(value)
+			if err != nil {
+				return nil, fmt.Errorf("importer: transaction %d: %w",
+					row.Line, err)
+			}
+
+			row.Date = date
+		case "TRNAMT":
+			amount, err := parseAmount(value, false)
+			if err != nil {
+				return nil, fmt.Errorf("importer: transaction %d: %w",
+					row.Line, err)
+			}
+
+			row.Amount = amount
+			hasAmount = true
+		case "NAME":
+			name = value
+		case "MEMO":
+			memo = value
+		}
+	}
+
+	if err := flush(); err != nil {
+		return nil, err
+	}
+
+	return rows, nil
+}
+
+// parseOFXDate parses the date of an OFX datetime such as
+// "20240305120000.000[-5:EST]"
+func parseOFXDate(s string) (toshl.Date, error) {
+	if len(s) < 8 {
+		return toshl.Date{}, fmt.Errorf("invalid date %q", s)
+	}
+
+	date, err := time.Parse("20060102", s[:8])
+	if err != nil {
+		return toshl.Date{}, fmt.Errorf("invalid date %q", s)
+	}
+
+	return toshl.Date(date), nil
+}
@@ -0,0 +1,21 @@
+package toshl
+
+import (
+	"log/slog"
+	"regexp"
+)
+
+// redactedFields are the JSON keys whose values are never logged
+var redactedFields = regexp.MustCompile(
+	`"(amount|balance|initial_balance|planned|limit|median|expenses|` +
+		`incomes|rate|token|access_token|refresh_token)"\s*:\s*` +
+		`("(?:[^"\\]|\\.)*"|-?[0-9][0-9.eE+-]*)`)
+
+// redactedBody is an API payload that hides amounts and tokens when
+// logged
+type redactedBody string
+
+func (b redactedBody) LogValue() slog.Value {
+	return slog.StringValue(
+		redactedFields.ReplaceAllString(string(b), `"$1":"[REDACTED]"`))
+}
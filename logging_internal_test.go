@@ -0,0 +1,45 @@
+package toshl
+
+import (
+	"bytes"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedactedBody(t *testing.T) {
+	body := redactedBody(`{"name":"Cash","balance": -3000.5,` +
+		`"currency":{"code":"USD","rate":1},"token":"secret"}`)
+
+	assert.Equal(t,
+		`{"name":"Cash","balance":"[REDACTED]",`+
+			`"currency":{"code":"USD","rate":"[REDACTED]"},`+
+			`"token":"[REDACTED]"}`,
+		body.LogValue().String())
+}
+
+func TestRestHTTPClientLogger(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`[{"id":"42","amount":-12.5}]`))
+		}))
+	defer server.Close()
+
+	var logs bytes.Buffer
+	c := &RestHTTPClient{
+		BaseURL: server.URL,
+		Token:   "secret-token",
+		Client:  server.Client(),
+		Logger: slog.New(slog.NewTextHandler(
+			&logs, &slog.HandlerOptions{Level: slog.LevelDebug})),
+	}
+
+	_, err := c.Get("entries", "")
+	assert.Nil(t, err)
+	assert.Contains(t, logs.String(), "path=/entries")
+	assert.NotContains(t, logs.String(), "secret-token")
+	assert.NotContains(t, logs.String(), "12.5")
+}
@@ -0,0 +1,346 @@
+package toshl
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Amount is an exact decimal amount of money, free of the rounding drift
+// of float64. Amounts are immutable and the zero value is 0.
+type Amount struct {
+	// coef is never mutated once set, so copies may share it
+	coef  *big.Int
+	scale int32
+}
+
+var bigTen = big.NewInt(10)
+
+// NewAmount returns the Amount units * 10^-scale, NewAmount(1250, 2) being
+// 12.50
+func NewAmount(units int64, scale int32) Amount {
+	if scale < 0 {
+		units *= pow10(-scale).Int64()
+		scale = 0
+	}
+
+	return Amount{coef: big.NewInt(units), scale: scale}
+}
+
+// AmountFromFloat returns the Amount with the shortest decimal
+// representation of f. It eases the migration of code using float64. It
+// panics when ParseFloatAmount fails, so floats from user input should go
+// through ParseFloatAmount instead.
+func AmountFromFloat(f float64) Amount {
+	a, err := ParseFloatAmount(f)
+	if err != nil {
+		panic(err)
+	}
+
+	return a
+}
+
+// ParseFloatAmount returns the Amount with the shortest decimal
+// representation of f. It fails for NaN, infinities and non-zero values
+// smaller than 1e-64 in magnitude.
+func ParseFloatAmount(f float64) (Amount, error) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return Amount{}, fmt.Errorf("toshl: invalid amount %v", f)
+	}
+
+	return ParseAmount(strconv.FormatFloat(f, 'f', -1, 64))
+}
+
+// maxAmountExponent bounds the exponent and the scale of parsed amounts,
+// so that a malformed value cannot make ParseAmount allocate huge numbers
+const maxAmountExponent = 64
+
+// ParseAmount parses a decimal number such as "-12.50" or "1.5e3"
+func ParseAmount(s string) (Amount, error) {
+	mantissa, exponent := s, int64(0)
+
+	if i := strings.IndexAny(s, "eE"); i >= 0 {
+		mantissa = s[:i]
+
+		var err error
+		exponent, err = strconv.ParseInt(s[i+1:], 10, 32)
+		if err != nil ||
+			exponent < -maxAmountExponent || exponent > maxAmountExponent {
+			return Amount{}, fmt.Errorf("toshl: invalid amount %q", s)
+		}
+	}
+
+	intPart, fracPart, _ := strings.Cut(mantissa, ".")
+	digits := intPart + fracPart
+
+	if strings.Trim(strings.TrimLeft(digits, "+-"), "0123456789") != "" ||
+		strings.TrimLeft(digits, "+-") == "" ||
+		strings.ContainsAny(fracPart, "+-") {
+		return Amount{}, fmt.Errorf("toshl: invalid amount %q", s)
+	}
+
+	coef, ok := new(big.Int).SetString(digits, 10)
+	if !ok {
+		return Amount{}, fmt.Errorf("toshl: invalid amount %q", s)
+	}
+
+	scale := int64(len(fracPart)) - exponent
+	if scale < -maxAmountExponent || scale > maxAmountExponent {
+		return Amount{}, fmt.Errorf("toshl: amount %q out of range", s)
+	}
+
+	if scale < 0 {
+		coef.Mul(coef, pow10(int32(-scale)))
+		scale = 0
+	}
+
+	return Amount{coef: coef, scale: int32(scale)}, nil
+}
+
+func pow10(n int32) *big.Int {
+	return new(big.Int).Exp(bigTen, big.NewInt(int64(n)), nil)
+}
+
+func (a Amount) bigCoef() *big.Int {
+	if a.coef == nil {
+		return new(big.Int)
+	}
+
+	return a.coef
+}
+
+// rescale returns the coefficient of a at the given larger scale
+func (a Amount) rescale(scale int32) *big.Int {
+	coef := a.bigCoef()
+	if scale == a.scale {
+		return coef
+	}
+
+	return new(big.Int).Mul(coef, pow10(scale-a.scale))
+}
+
+// Scale returns the number of decimal places of a
+func (a Amount) Scale() int32 {
+	return a.scale
+}
+
+// Add returns a + b
+func (a Amount) Add(b Amount) Amount {
+	scale := max(a.scale, b.scale)
+	return Amount{
+		coef:  new(big.Int).Add(a.rescale(scale), b.rescale(scale)),
+		scale: scale,
+	}
+}
+
+// Sub returns a - b
+func (a Amount) Sub(b Amount) Amount {
+	return a.Add(b.Neg())
+}
+
+// Neg returns -a
+func (a Amount) Neg() Amount {
+	return Amount{coef: new(big.Int).Neg(a.bigCoef()), scale: a.scale}
+}
+
+// Abs returns |a|
+func (a Amount) Abs() Amount {
+	if a.Sign() < 0 {
+		return a.Neg()
+	}
+
+	return a
+}
+
+// Mul returns a * b
+func (a Amount) Mul(b Amount) Amount {
+	return Amount{
+		coef:  new(big.Int).Mul(a.bigCoef(), b.bigCoef()),
+		scale: a.scale + b.scale,
+	}
+}
+
+// Div returns a / b rounded half away from zero to scale decimal places.
+// It panics when b is zero.
+func (a Amount) Div(b Amount, scale int32) Amount {
+	if b.IsZero() {
+		panic("toshl: division of an amount by zero")
+	}
+
+	// a / b = (ca / cb) * 10^(sb - sa), computed with one extra digit
+	// to round the result
+	shift := scale + 1 + b.scale - a.scale
+	num := new(big.Int).Mul(a.bigCoef(), pow10(max(0, shift)))
+	den := new(big.Int).Mul(b.bigCoef(), pow10(max(0, -shift)))
+
+	quo := new(big.Int).Quo(num, den)
+
+	return Amount{coef: quo, scale: scale + 1}.Round(scale)
+}
+
+// Round returns a rounded half away from zero to scale decimal places
+func (a Amount) Round(scale int32) Amount {
+	if scale >= a.scale {
+		return Amount{coef: a.rescale(scale), scale: scale}
+	}
+
+	factor := pow10(a.scale - scale)
+	quo, rem := new(big.Int).QuoRem(a.bigCoef(), factor, new(big.Int))
+
+	// Round away from zero when the remainder is at least half the factor
+	if rem.Abs(rem).Mul(rem, big.NewInt(2)).Cmp(factor) >= 0 {
+		quo.Add(quo, big.NewInt(int64(a.Sign())))
+	}
+
+	return Amount{coef: quo, scale: scale}
+}
+
+// Cmp compares a and b and returns -1, 0 or +1
+func (a Amount) Cmp(b Amount) int {
+	scale := max(a.scale, b.scale)
+	return a.rescale(scale).Cmp(b.rescale(scale))
+}
+
+// Equal reports whether a and b are the same number, regardless of scale
+func (a Amount) Equal(b Amount) bool {
+	return a.Cmp(b) == 0
+}
+
+// Sign returns -1, 0 or +1 depending on the sign of a
+func (a Amount) Sign() int {
+	return a.bigCoef().Sign()
+}
+
+// IsZero reports whether a is 0
+func (a Amount) IsZero() bool {
+	return a.Sign() == 0
+}
+
+// Float64 returns the nearest float64 to a
+func (a Amount) Float64() float64 {
+	f, _ := strconv.ParseFloat(a.String(), 64)
+	return f
+}
+
+// String returns a as a decimal number keeping its scale, e.g. "-12.50"
+func (a Amount) String() string {
+	digits := new(big.Int).Abs(a.bigCoef()).String()
+
+	if a.scale > 0 {
+		if pad := int(a.scale) - len(digits) + 1; pad > 0 {
+			digits = strings.Repeat("0", pad) + digits
+		}
+
+		point := len(digits) - int(a.scale)
+		digits = digits[:point] + "." + digits[point:]
+	}
+
+	if a.Sign() < 0 {
+		return "-" + digits
+	}
+
+	return digits
+}
+
+// MarshalJSON encodes a as a JSON number
+func (a Amount) MarshalJSON() ([]byte, error) {
+	return []byte(a.String()), nil
+}
+
+// UnmarshalJSON decodes a JSON number, or a string holding one
+func (a *Amount) UnmarshalJSON(b []byte) error {
+	if bytes.Equal(b, []byte("null")) {
+		return nil
+	}
+
+	parsed, err := ParseAmount(string(bytes.Trim(b, `"`)))
+	if err != nil {
+		return err
+	}
+
+	*a = parsed
+	return nil
+}
+
+// currencyPrecisions lists the ISO 4217 currencies not using 2 decimals
+var currencyPrecisions = map[string]int32{
+	"BHD": 3, "BIF": 0, "CLP": 0, "DJF": 0, "GNF": 0, "IQD": 3, "ISK": 0,
+	"JOD": 3, "JPY": 0, "KMF": 0, "KRW": 0, "KWD": 3, "LYD": 3, "OMR": 3,
+	"PYG": 0, "RWF": 0, "TND": 3, "UGX": 0, "UYI": 0, "VND": 0, "VUV": 0,
+	"XAF": 0, "XOF": 0, "XPF": 0, "BTC": 8, "ETH": 8,
+}
+
+// CurrencyPrecision returns the number of decimal places of the minor unit
+// of a currency, 2 for unknown currencies
+func CurrencyPrecision(code string) int32 {
+	if precision, ok := currencyPrecisions[strings.ToUpper(code)]; ok {
+		return precision
+	}
+
+	return 2
+}
+
+// ErrCurrencyMismatch is returned by operations mixing currencies
+var ErrCurrencyMismatch = errors.New("toshl: currencies do not match")
+
+// Money is an Amount in a given currency
+type Money struct {
+	Amount   Amount
+	Currency string
+}
+
+// MoneyFromMinorUnits returns the Money worth units of the minor unit of
+// currency, e.g. cents for USD
+func MoneyFromMinorUnits(units int64, currency string) Money {
+	return Money{
+		Amount:   NewAmount(units, CurrencyPrecision(currency)),
+		Currency: currency,
+	}
+}
+
+// MinorUnits returns m in the minor unit of its currency, rounded half
+// away from zero
+func (m Money) MinorUnits() *big.Int {
+	return new(big.Int).Set(m.Round().Amount.bigCoef())
+}
+
+// Round returns m rounded to the precision of its currency
+func (m Money) Round() Money {
+	return Money{
+		Amount:   m.Amount.Round(CurrencyPrecision(m.Currency)),
+		Currency: m.Currency,
+	}
+}
+
+// Add returns m + o, both having to be in the same currency
+func (m Money) Add(o Money) (Money, error) {
+	if m.Currency != o.Currency {
+		return Money{}, ErrCurrencyMismatch
+	}
+
+	return Money{Amount: m.Amount.Add(o.Amount), Currency: m.Currency}, nil
+}
+
+// Sub returns m - o, both having to be in the same currency
+func (m Money) Sub(o Money) (Money, error) {
+	return m.Add(Money{Amount: o.Amount.Neg(), Currency: o.Currency})
+}
+
+// Cmp compares m and o, both having to be in the same currency
+func (m Money) Cmp(o Money) (int, error) {
+	if m.Currency != o.Currency {
+		return 0, ErrCurrencyMismatch
+	}
+
+	return m.Amount.Cmp(o.Amount), nil
+}
+
+// String returns m rounded to its currency precision followed by its
+// currency code, e.g. "12.50 USD"
+func (m Money) String() string {
+	return m.Round().Amount.String() + " " + m.Currency
+}
@@ -0,0 +1,137 @@
+package toshl_test
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/Philanthropists/toshl-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func mustParseAmount(t *testing.T, s string) toshl.Amount {
+	a, err := toshl.ParseAmount(s)
+	assert.Nil(t, err)
+	return a
+}
+
+func TestAmountParse(t *testing.T) {
+	for input, expected := range map[string]string{
+		"0":        "0",
+		"-12.50":   "-12.50",
+		"+3":       "3",
+		".5":       "0.5",
+		"0.001":    "0.001",
+		"1.5e3":    "1500",
+		"12.345E1": "123.45",
+		"25e-3":    "0.025",
+	} {
+		assert.Equal(t, expected, mustParseAmount(t, input).String(), input)
+	}
+
+	for _, input := range []string{"", "-", "abc", "1-2", "1.2.3", "1e"} {
+		_, err := toshl.ParseAmount(input)
+		assert.NotNil(t, err, input)
+	}
+}
+
+func TestAmountParseOutOfRange(t *testing.T) {
+	for _, input := range []string{
+		"1e200000000", "1e-2147483647", "1e65", "1e-65", "0.1e-64",
+	} {
+		_, err := toshl.ParseAmount(input)
+		assert.NotNil(t, err, input)
+	}
+
+	assert.Equal(t, 65, len(mustParseAmount(t, "1e64").String()))
+	assert.Equal(t, "0.01", mustParseAmount(t, "0.1e-1").String())
+}
+
+func TestAmountNoDrift(t *testing.T) {
+	sum := toshl.Amount{}
+	tenCents := toshl.NewAmount(10, 2)
+
+	for i := 0; i < 1000; i++ {
+		sum = sum.Add(tenCents)
+	}
+
+	assert.Equal(t, "100.00", sum.String())
+	assert.True(t, sum.Equal(toshl.NewAmount(100, 0)))
+}
+
+func TestAmountArithmetic(t *testing.T) {
+	a := mustParseAmount(t, "10.25")
+	b := mustParseAmount(t, "-3.1")
+
+	assert.Equal(t, "7.15", a.Add(b).String())
+	assert.Equal(t, "13.35", a.Sub(b).String())
+	assert.Equal(t, "-31.775", a.Mul(b).String())
+	assert.Equal(t, "-3.31", a.Div(b, 2).String())
+	assert.Equal(t, "3.1", b.Abs().String())
+	assert.Equal(t, 1, a.Cmp(b))
+	assert.Equal(t, -1, b.Sign())
+	assert.True(t, toshl.Amount{}.IsZero())
+	assert.Equal(t, 10.25, a.Float64())
+}
+
+func TestAmountRound(t *testing.T) {
+	assert.Equal(t, "2.35", mustParseAmount(t, "2.345").Round(2).String())
+	assert.Equal(t, "-2.35", mustParseAmount(t, "-2.345").Round(2).String())
+	assert.Equal(t, "2.34", mustParseAmount(t, "2.3449").Round(2).String())
+	assert.Equal(t, "3.00", mustParseAmount(t, "3").Round(2).String())
+	assert.Equal(t, "1", mustParseAmount(t, "0.5").Round(0).String())
+}
+
+func TestAmountJSON(t *testing.T) {
+	var entry toshl.Entry
+	err := json.Unmarshal([]byte(`{"amount": -1234.56}`), &entry)
+	assert.Nil(t, err)
+	assert.Equal(t, "-1234.56", entry.Amount.String())
+
+	var quoted toshl.Amount
+	assert.Nil(t, json.Unmarshal([]byte(`"0.10"`), &quoted))
+	assert.Equal(t, "0.10", quoted.String())
+
+	bs, err := json.Marshal(struct {
+		Amount toshl.Amount `json:"amount"`
+	}{mustParseAmount(t, "-0.05")})
+	assert.Nil(t, err)
+	assert.Equal(t, `{"amount":-0.05}`, string(bs))
+}
+
+func TestAmountFromFloat(t *testing.T) {
+	assert.Equal(t, "0.1", toshl.AmountFromFloat(0.1).String())
+	assert.Equal(t, "-3000", toshl.AmountFromFloat(-3000).String())
+}
+
+func TestParseFloatAmount(t *testing.T) {
+	a, err := toshl.ParseFloatAmount(12.5)
+	assert.Nil(t, err)
+	assert.Equal(t, "12.5", a.String())
+
+	for _, f := range []float64{math.NaN(), math.Inf(1), math.Inf(-1), 1e-70} {
+		_, err := toshl.ParseFloatAmount(f)
+		assert.Error(t, err, "%v", f)
+	}
+
+	assert.Panics(t, func() { toshl.AmountFromFloat(math.NaN()) })
+}
+
+func TestMoney(t *testing.T) {
+	usd := toshl.MoneyFromMinorUnits(1999, "USD")
+	assert.Equal(t, "19.99 USD", usd.String())
+
+	jpy := toshl.Money{Amount: mustParseAmount(t, "1234.5"), Currency: "JPY"}
+	assert.Equal(t, "1235 JPY", jpy.String())
+	assert.Equal(t, int64(1235), jpy.MinorUnits().Int64())
+
+	kwd := toshl.Money{Amount: mustParseAmount(t, "1.2345"), Currency: "KWD"}
+	assert.Equal(t, int64(1235), kwd.MinorUnits().Int64())
+
+	total, err := usd.Add(toshl.MoneyFromMinorUnits(1, "USD"))
+	assert.Nil(t, err)
+	assert.Equal(t, "20.00 USD", total.String())
+
+	_, err = usd.Add(jpy)
+	assert.ErrorIs(t, err, toshl.ErrCurrencyMismatch)
+}
@@ -0,0 +1,35 @@
+package toshl
+
+import "log/slog"
+
+// ClientOption configures the Client returned by NewClient
+type ClientOption func(*clientOptions)
+
+type clientOptions struct {
+	logger      *slog.Logger
+	retryPolicy *RetryPolicy
+	rateLimiter *RateLimiter
+}
+
+// WithLogger enables logging of requests and failures. Nothing is logged
+// unless a logger is given.
+func WithLogger(logger *slog.Logger) ClientOption {
+	return func(o *clientOptions) {
+		o.logger = logger
+	}
+}
+
+// WithRetryPolicy enables retries of failed requests
+func WithRetryPolicy(policy RetryPolicy) ClientOption {
+	return func(o *clientOptions) {
+		o.retryPolicy = &policy
+	}
+}
+
+// WithRateLimiter throttles requests with the given RateLimiter, which can
+// be shared with other clients
+func WithRateLimiter(limiter *RateLimiter) ClientOption {
+	return func(o *clientOptions) {
+		o.rateLimiter = limiter
+	}
+}
@@ -0,0 +1,200 @@
+package toshl
+
+import (
+	"context"
+	"iter"
+)
+
+// Cursor identifies a page of a list endpoint. It can be stored to resume
+// an iteration later with Pages.
+type Cursor struct {
+	Path  string `json:"path"`
+	Query string `json:"query"`
+}
+
+// Page represents a single page of a list endpoint
+type Page[T any] struct {
+	Items []T
+
+	// Cursor identifies this page
+	Cursor Cursor
+
+	// Next identifies the following page, nil on the last page
+	Next *Cursor
+}
+
+// Pages fetches the pages of a list endpoint lazily, starting at cursor
+// and following the Link header. Iteration stops at the first error or
+// when the caller breaks out of the loop. HTTPClients that are not
+// PageGetters yield a single page holding every item.
+func Pages[T any](
+	ctx context.Context, c *Client, cursor Cursor,
+) iter.Seq2[*Page[T], error] {
+	return func(yield func(*Page[T], error) bool) {
+		next := &cursor
+
+		for next != nil {
+			if err := ctx.Err(); err != nil {
+				yield(nil, err)
+				return
+			}
+
+			page := &Page[T]{Cursor: *next}
+
+			res, nextQuery, err := getPage(
+				ctx, c.client, next.Path, next.Query)
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+
+			err = c.decode(ctx, res, &page.Items)
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+
+			if nextQuery != "" {
+				page.Next = &Cursor{Path: next.Path, Query: nextQuery}
+			}
+
+			if !yield(page, nil) {
+				return
+			}
+
+			next = page.Next
+		}
+	}
+}
+
+// Items flattens an iterator over pages into an iterator over their items
+func Items[T any](pages iter.Seq2[*Page[T], error]) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		for page, err := range pages {
+			if err != nil {
+				var zero T
+				yield(zero, err)
+				return
+			}
+
+			for _, item := range page.Items {
+				if !yield(item, nil) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// failed returns an iterator yielding only err
+func failed[T any](err error) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var zero T
+		yield(zero, err)
+	}
+}
+
+// AccountPages returns an iterator over every page of Accounts
+func (c *Client) AccountPages(
+	ctx context.Context, params *AccountQueryParams,
+) iter.Seq2[*Page[Account], error] {
+	cursor := Cursor{Path: "accounts"}
+
+	if params != nil {
+		cursor.Query = params.getQueryString()
+	}
+
+	return Pages[Account](ctx, c, cursor)
+}
+
+// AllAccounts returns an iterator over the Accounts of every page
+func (c *Client) AllAccounts(
+	ctx context.Context, params *AccountQueryParams,
+) iter.Seq2[Account, error] {
+	return Items(c.AccountPages(ctx, params))
+}
+
+// BudgetPages returns an iterator over every page of Budgets
+func (c *Client) BudgetPages(
+	ctx context.Context, params *BudgetQueryParams,
+) iter.Seq2[*Page[Budget], error] {
+	cursor := Cursor{Path: "budgets"}
+
+	if params != nil {
+		cursor.Query = params.getQueryString()
+	}
+
+	return Pages[Budget](ctx, c, cursor)
+}
+
+// AllBudgets returns an iterator over the Budgets of every page
+func (c *Client) AllBudgets(
+	ctx context.Context, params *BudgetQueryParams,
+) iter.Seq2[Budget, error] {
+	return Items(c.BudgetPages(ctx, params))
+}
+
+// CategoryPages returns an iterator over every page of Categories
+func (c *Client) CategoryPages(
+	ctx context.Context, params *CategoryQueryParams,
+) iter.Seq2[*Page[Category], error] {
+	cursor := Cursor{Path: "categories"}
+
+	if params != nil {
+		cursor.Query = params.getQueryString()
+	}
+
+	return Pages[Category](ctx, c, cursor)
+}
+
+// AllCategories returns an iterator over the Categories of every page
+func (c *Client) AllCategories(
+	ctx context.Context, params *CategoryQueryParams,
+) iter.Seq2[Category, error] {
+	return Items(c.CategoryPages(ctx, params))
+}
+
+// TagPages returns an iterator over every page of Tags
+func (c *Client) TagPages(
+	ctx context.Context, params *TagQueryParams,
+) iter.Seq2[*Page[Tag], error] {
+	cursor := Cursor{Path: "tags"}
+
+	if params != nil {
+		cursor.Query = params.getQueryString()
+	}
+
+	return Pages[Tag](ctx, c, cursor)
+}
+
+// AllTags returns an iterator over the Tags of every page
+func (c *Client) AllTags(
+	ctx context.Context, params *TagQueryParams,
+) iter.Seq2[Tag, error] {
+	return Items(c.TagPages(ctx, params))
+}
+
+// EntryPages returns an iterator over every page of Entries
+func (c *Client) EntryPages(
+	ctx context.Context, params *EntryQueryParams,
+) iter.Seq2[*Page[Entry], error] {
+	cursor := Cursor{Path: "entries"}
+
+	if params != nil {
+		queryString, err := params.getQueryString()
+		if err != nil {
+			return failed[*Page[Entry]](err)
+		}
+
+		cursor.Query = queryString
+	}
+
+	return Pages[Entry](ctx, c, cursor)
+}
+
+// AllEntries returns an iterator over the Entries of every page
+func (c *Client) AllEntries(
+	ctx context.Context, params *EntryQueryParams,
+) iter.Seq2[Entry, error] {
+	return Items(c.EntryPages(ctx, params))
+}
@@ -0,0 +1,167 @@
+package toshl_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/Philanthropists/toshl-go"
+	"github.com/stretchr/testify/assert"
+)
+
+// newPagedClient returns a Client backed by a server listing accounts in
+// pages of one, and the number of requests it received
+func newPagedClient(t *testing.T, pages int) (*toshl.Client, *int) {
+	requests := 0
+
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			requests++
+
+			page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+			if page < pages-1 {
+				w.Header().Set("Link", fmt.Sprintf(
+					`<https://api.toshl.com/accounts?page=%d>; rel="next"`,
+					page+1))
+			}
+
+			fmt.Fprintf(w, `[{"id": "%d", "name": "Account %d"}]`,
+				page, page)
+		}))
+	t.Cleanup(server.Close)
+
+	httpClient := &toshl.RestHTTPClient{
+		BaseURL: server.URL,
+		Client:  server.Client(),
+	}
+
+	return toshl.NewClient("token", httpClient), &requests
+}
+
+func TestClientAllAccounts(t *testing.T) {
+	c, requests := newPagedClient(t, 3)
+
+	var names []string
+	for account, err := range c.AllAccounts(context.Background(), nil) {
+		assert.Nil(t, err)
+		names = append(names, account.Name)
+	}
+
+	assert.Equal(t, []string{"Account 0", "Account 1", "Account 2"}, names)
+	assert.Equal(t, 3, *requests)
+}
+
+func TestClientAllAccountsBreak(t *testing.T) {
+	c, requests := newPagedClient(t, 3)
+
+	for account, err := range c.AllAccounts(context.Background(), nil) {
+		assert.Nil(t, err)
+		assert.Equal(t, "Account 0", account.Name)
+		break
+	}
+
+	assert.Equal(t, 1, *requests)
+}
+
+func TestPagesResume(t *testing.T) {
+	c, _ := newPagedClient(t, 3)
+	ctx := context.Background()
+
+	var next *toshl.Cursor
+	for page, err := range c.AccountPages(ctx, nil) {
+		assert.Nil(t, err)
+		next = page.Next
+		break
+	}
+
+	assert.Equal(t, toshl.Cursor{Path: "accounts", Query: "page=1"}, *next)
+
+	var ids []string
+	for page, err := range toshl.Pages[toshl.Account](ctx, c, *next) {
+		assert.Nil(t, err)
+		ids = append(ids, *page.Items[0].ID)
+	}
+
+	assert.Equal(t, []string{"1", "2"}, ids)
+}
+
+func TestClientAllEntriesInvalidParams(t *testing.T) {
+	c, requests := newPagedClient(t, 1)
+
+	for _, err := range c.AllEntries(
+		context.Background(), &toshl.EntryQueryParams{},
+	) {
+		assert.NotNil(t, err)
+	}
+
+	assert.Equal(t, 0, *requests)
+}
+
+// basicHTTPClient is a hand-written HTTPClient implementing none of the
+// optional interfaces, listing accounts in pages of one
+type basicHTTPClient struct{}
+
+func (basicHTTPClient) Get(APIUrl, queryString string) (string, error) {
+	return "file of " + APIUrl, nil
+}
+
+func (basicHTTPClient) GetMultiple(
+	APIUrl, queryString string,
+) ([]string, error) {
+	return []string{`[{"id": "0"}]`, `[{"id": "1"}, {"id": "2"}]`}, nil
+}
+
+func (basicHTTPClient) Post(APIUrl, JSONPayload string) (string, error) {
+	return "", nil
+}
+
+func (basicHTTPClient) Update(APIUrl, JSONPayload string) (string, error) {
+	return "", nil
+}
+
+func (basicHTTPClient) Delete(APIUrl string) error {
+	return nil
+}
+
+func TestPagesWithoutPageGetter(t *testing.T) {
+	c := toshl.NewClient("", basicHTTPClient{})
+
+	var ids []string
+	for account, err := range c.AllAccounts(context.Background(), nil) {
+		assert.Nil(t, err)
+		ids = append(ids, *account.ID)
+	}
+
+	assert.Equal(t, []string{"0", "1", "2"}, ids)
+}
+
+func TestDownloadWithoutDownloader(t *testing.T) {
+	c := toshl.NewClient("", basicHTTPClient{})
+
+	var file bytes.Buffer
+	assert.Nil(t, c.DownloadExport("7", &file))
+	assert.Equal(t, "file of exports/7/download", file.String())
+}
+
+func TestUploadWithoutUploader(t *testing.T) {
+	c := toshl.NewClient("", basicHTTPClient{})
+
+	_, err := c.UploadImage("receipt.png", bytes.NewReader(nil))
+	assert.ErrorIs(t, err, toshl.ErrUploadUnsupported)
+}
+
+func TestContextWithoutContextHTTPClient(t *testing.T) {
+	c := toshl.NewClient("", basicHTTPClient{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var file bytes.Buffer
+	err := c.DownloadExportContext(ctx, "7", &file)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Empty(t, file.String())
+}
@@ -0,0 +1,158 @@
+package toshl
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ErrRateLimited is returned instead of waiting by a fail fast RateLimiter
+var ErrRateLimited = errors.New("toshl: client rate limit exceeded")
+
+// RateLimiterConfig configures a RateLimiter
+type RateLimiterConfig struct {
+	// Rate is the number of requests allowed per second. Zero or less
+	// means no client side limit, only the pauses the API asks for.
+	Rate float64
+
+	// Burst is the number of requests that can be sent at once
+	Burst int
+
+	// FailFast returns ErrRateLimited instead of waiting when a request
+	// is not allowed yet
+	FailFast bool
+}
+
+// RateLimiter is a token bucket limiting the rate of API requests. It also
+// pauses requests when the X-RateLimit-* or Retry-After response headers
+// report that the API limits have been reached. It is safe for concurrent
+// use and can be shared by several clients using the same token.
+type RateLimiter struct {
+	config RateLimiterConfig
+
+	mu          sync.Mutex
+	tokens      float64
+	last        time.Time
+	pausedUntil time.Time
+}
+
+// NewRateLimiter returns a RateLimiter with a full bucket
+func NewRateLimiter(config RateLimiterConfig) *RateLimiter {
+	if config.Burst < 1 {
+		config.Burst = 1
+	}
+
+	return &RateLimiter{
+		config: config,
+		tokens: float64(config.Burst),
+		last:   time.Now(),
+	}
+}
+
+// Wait blocks until a request is allowed, ctx is done or, in fail fast
+// mode, returns ErrRateLimited right away
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	l.mu.Lock()
+
+	now := time.Now()
+	l.refill(now)
+
+	unlimited := l.config.Rate <= 0
+
+	var delay time.Duration
+	if l.tokens < 1 && !unlimited {
+		delay = time.Duration(
+			(1 - l.tokens) / l.config.Rate * float64(time.Second))
+	}
+
+	if now.Before(l.pausedUntil) {
+		delay = max(delay, l.pausedUntil.Sub(now))
+	}
+
+	if delay > 0 && l.config.FailFast {
+		l.mu.Unlock()
+		return ErrRateLimited
+	}
+
+	// Reserve the token now so concurrent callers queue up behind us
+	if !unlimited {
+		l.tokens--
+	}
+	l.mu.Unlock()
+
+	if delay <= 0 {
+		return nil
+	}
+
+	if err := sleep(ctx, delay); err != nil {
+		if !unlimited {
+			l.mu.Lock()
+			l.tokens++
+			l.mu.Unlock()
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (l *RateLimiter) refill(now time.Time) {
+	elapsed := now.Sub(l.last).Seconds()
+	l.last = now
+
+	l.tokens = min(float64(l.config.Burst), l.tokens+elapsed*l.config.Rate)
+}
+
+// observe adapts the limiter to the limits reported by the API
+func (l *RateLimiter) observe(resp *http.Response) {
+	now := time.Now()
+
+	var pauseUntil time.Time
+
+	retryAfter := parseRetryAfter(resp.Header.Get("Retry-After"))
+	if retryAfter > 0 {
+		pauseUntil = now.Add(retryAfter)
+	}
+
+	remaining, err := strconv.Atoi(resp.Header.Get("X-RateLimit-Remaining"))
+	hasRemaining := err == nil
+
+	if hasRemaining && remaining <= 0 {
+		reset := parseRateLimitReset(
+			resp.Header.Get("X-RateLimit-Reset"), now)
+		if reset.After(pauseUntil) {
+			pauseUntil = reset
+		}
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if pauseUntil.After(l.pausedUntil) {
+		l.pausedUntil = pauseUntil
+	}
+
+	if hasRemaining {
+		l.refill(now)
+		l.tokens = min(l.tokens, float64(remaining))
+	}
+}
+
+// parseRateLimitReset parses X-RateLimit-Reset given either as a Unix
+// timestamp or as a number of seconds from now
+func parseRateLimitReset(value string, now time.Time) time.Time {
+	reset, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || reset <= 0 {
+		return time.Time{}
+	}
+
+	// Anything larger than a year of seconds is a timestamp
+	if reset > 365*24*60*60 {
+		return time.Unix(reset, 0)
+	}
+
+	return now.Add(time.Duration(reset) * time.Second)
+}
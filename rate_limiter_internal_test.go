@@ -0,0 +1,114 @@
+package toshl
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimiterBurstThenWait(t *testing.T) {
+	l := NewRateLimiter(RateLimiterConfig{Rate: 100, Burst: 2})
+	ctx := context.Background()
+
+	start := time.Now()
+	assert.Nil(t, l.Wait(ctx))
+	assert.Nil(t, l.Wait(ctx))
+	assert.Less(t, time.Since(start), 5*time.Millisecond)
+
+	assert.Nil(t, l.Wait(ctx))
+	assert.GreaterOrEqual(t, time.Since(start), 5*time.Millisecond)
+}
+
+func TestRateLimiterFailFast(t *testing.T) {
+	l := NewRateLimiter(RateLimiterConfig{Rate: 1, Burst: 1, FailFast: true})
+
+	assert.Nil(t, l.Wait(context.Background()))
+	assert.ErrorIs(t, l.Wait(context.Background()), ErrRateLimited)
+}
+
+func TestRateLimiterWaitCancelled(t *testing.T) {
+	l := NewRateLimiter(RateLimiterConfig{Rate: 0.001, Burst: 1})
+	assert.Nil(t, l.Wait(context.Background()))
+
+	ctx, cancel := context.WithTimeout(
+		context.Background(), time.Millisecond)
+	defer cancel()
+
+	assert.ErrorIs(t, l.Wait(ctx), context.DeadlineExceeded)
+}
+
+func TestRateLimiterUnlimited(t *testing.T) {
+	l := NewRateLimiter(RateLimiterConfig{Burst: 1, FailFast: true})
+
+	for i := 0; i < 10; i++ {
+		assert.Nil(t, l.Wait(context.Background()))
+	}
+
+	// The pauses the API asks for still apply
+	l.observe(&http.Response{Header: http.Header{"Retry-After": {"60"}}})
+	assert.ErrorIs(t, l.Wait(context.Background()), ErrRateLimited)
+}
+
+func TestRateLimiterConcurrentUse(t *testing.T) {
+	l := NewRateLimiter(RateLimiterConfig{Rate: 1000, Burst: 5})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.Nil(t, l.Wait(context.Background()))
+		}()
+	}
+	wg.Wait()
+}
+
+func TestRateLimiterObservesHeaders(t *testing.T) {
+	l := NewRateLimiter(RateLimiterConfig{
+		Rate: 100, Burst: 10, FailFast: true,
+	})
+
+	l.observe(&http.Response{Header: http.Header{
+		"X-Ratelimit-Remaining": {"0"},
+		"X-Ratelimit-Reset":     {"60"},
+	}})
+
+	assert.ErrorIs(t, l.Wait(context.Background()), ErrRateLimited)
+}
+
+func TestParseRateLimitReset(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+
+	assert.Equal(t, now.Add(30*time.Second), parseRateLimitReset("30", now))
+	assert.Equal(t, time.Unix(1600000100, 0),
+		parseRateLimitReset("1600000100", now))
+	assert.True(t, parseRateLimitReset("", now).IsZero())
+}
+
+func TestRestHTTPClientRateLimiter(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`[]`))
+		}))
+	defer server.Close()
+
+	c := &RestHTTPClient{
+		BaseURL: server.URL,
+		Client:  server.Client(),
+		RateLimiter: NewRateLimiter(RateLimiterConfig{
+			Rate: 0.001, Burst: 1, FailFast: true,
+		}),
+		RetryPolicy: &RetryPolicy{MaxAttempts: 3},
+	}
+
+	_, err := c.Get("accounts", "")
+	assert.Nil(t, err)
+
+	_, err = c.Get("accounts", "")
+	assert.ErrorIs(t, err, ErrRateLimited)
+}
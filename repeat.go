@@ -0,0 +1,526 @@
+package toshl
+
+import (
+	"errors"
+	"fmt"
+	"iter"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// weekdays maps the RRULE weekday codes used by ByDay to weekdays
+var weekdays = map[string]time.Weekday{
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+	"SU": time.Sunday,
+}
+
+// byDay is a ByDay item, like "FR" or "-1FR" for the last Friday of the
+// month. n is 0 when every such weekday of the period matches.
+type byDay struct {
+	n       int
+	weekday time.Weekday
+}
+
+// rule is a validated Repeat, with its lists parsed
+type rule struct {
+	start      time.Time
+	end        time.Time
+	frequency  RepeatFrequency
+	interval   int
+	count      int
+	byDay      []byDay
+	byMonthDay []int
+	bySetPos   []int
+}
+
+// Validate checks that the Repeat is a recurrence rule Occurrences can
+// expand, reporting every invalid field
+func (r *Repeat) Validate() error {
+	_, err := r.rule()
+	return err
+}
+
+func (r *Repeat) rule() (*rule, error) {
+	var errMsg string
+
+	rl := &rule{
+		start:     dateOnly(time.Time(r.Start)),
+		frequency: r.Frequency,
+		interval:  max(int(r.Interval), 1),
+		count:     int(r.Count),
+	}
+
+	if !time.Time(r.End).IsZero() {
+		rl.end = dateOnly(time.Time(r.End))
+	}
+
+	switch r.Frequency {
+	case Daily, Weekly, Monthly, Yearly:
+	default:
+		errMsg = errMsg + fmt.Sprintf("invalid 'frequency' %q;", r.Frequency)
+	}
+
+	if time.Time(r.Start).IsZero() {
+		errMsg = errMsg + "'start' field is mandatory;"
+	} else if !rl.end.IsZero() && rl.end.Before(rl.start) {
+		errMsg = errMsg + "'end' must not be before 'start';"
+	}
+
+	days, err := parseByDay(r.ByDay)
+	if err != nil {
+		errMsg = errMsg + fmt.Sprintf("invalid 'byday' %q;", r.ByDay)
+	}
+
+	rl.byDay = days
+
+	for _, day := range days {
+		if day.n != 0 && (r.Frequency == Daily || r.Frequency == Weekly) {
+			errMsg = errMsg +
+				"'byday' ordinals need a monthly or yearly 'frequency';"
+			break
+		}
+	}
+
+	monthDays, err := parseInts(r.ByMonthDay, 31)
+	if err != nil {
+		errMsg = errMsg + fmt.Sprintf("invalid 'bymonthday' %q;", r.ByMonthDay)
+	} else if len(monthDays) > 0 && r.Frequency == Weekly {
+		errMsg = errMsg +
+			"'bymonthday' cannot be used with a weekly 'frequency';"
+	}
+
+	rl.byMonthDay = monthDays
+
+	positions, err := parseInts(r.BySetPos, 366)
+	if err != nil {
+		errMsg = errMsg + fmt.Sprintf("invalid 'bysetpos' %q;", r.BySetPos)
+	} else if len(positions) > 0 && len(days) == 0 && len(monthDays) == 0 {
+		errMsg = errMsg + "'bysetpos' needs 'byday' or 'bymonthday';"
+	}
+
+	rl.bySetPos = positions
+
+	if errMsg != "" {
+		return nil, errors.New(errMsg)
+	}
+
+	return rl, nil
+}
+
+// parseByDay parses a comma separated list of weekday codes, each
+// optionally preceded by a signed ordinal
+func parseByDay(s string) ([]byDay, error) {
+	var days []byDay
+
+	for _, item := range splitList(s) {
+		item = strings.ToUpper(item)
+		if len(item) < 2 {
+			return nil, fmt.Errorf("invalid weekday %q", item)
+		}
+
+		weekday, ok := weekdays[item[len(item)-2:]]
+		if !ok {
+			return nil, fmt.Errorf("invalid weekday %q", item)
+		}
+
+		day := byDay{weekday: weekday}
+
+		if ordinal := item[:len(item)-2]; ordinal != "" {
+			n, err := strconv.Atoi(ordinal)
+			if err != nil || n == 0 || n < -5 || n > 5 {
+				return nil, fmt.Errorf("invalid ordinal %q", ordinal)
+			}
+
+			day.n = n
+		}
+
+		days = append(days, day)
+	}
+
+	return days, nil
+}
+
+// parseInts parses a comma separated list of non zero integers between
+// -limit and limit
+func parseInts(s string, limit int) ([]int, error) {
+	var ints []int
+
+	for _, item := range splitList(s) {
+		n, err := strconv.Atoi(item)
+		if err != nil {
+			return nil, err
+		}
+
+		if n == 0 || n < -limit || n > limit {
+			return nil, fmt.Errorf("%d out of range", n)
+		}
+
+		ints = append(ints, n)
+	}
+
+	return ints, nil
+}
+
+func splitList(s string) []string {
+	var items []string
+
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
+
+// Occurrences returns the dates of the Repeat between from and to,
+// inclusive. The rule is expanded from Start so that Count holds, like an
+// RRULE whose week starts on Monday:
+//   - weekly rules fall on the ByDay weekdays, the Start one by default
+//   - monthly rules fall on the ByMonthDay days and ByDay weekdays of the
+//     month, the Start day by default; months without that day are skipped
+//   - yearly rules are monthly rules restricted to the month of Start
+//   - BySetPos picks the nth dates of each period, negative from the end
+func (r *Repeat) Occurrences(from, to Date) ([]Date, error) {
+	rl, err := r.rule()
+	if err != nil {
+		return nil, err
+	}
+
+	first := dateOnly(time.Time(from))
+	last := dateOnly(time.Time(to))
+
+	var dates []Date
+	for date := range rl.dates(last) {
+		if !date.Before(first) {
+			dates = append(dates, Date(date))
+		}
+	}
+
+	return dates, nil
+}
+
+// dates yields the occurrences of the rule in order, up to limit
+func (rl *rule) dates(limit time.Time) iter.Seq[time.Time] {
+	if !rl.end.IsZero() && rl.end.Before(limit) {
+		limit = rl.end
+	}
+
+	return func(yield func(time.Time) bool) {
+		yielded := 0
+
+		period := rl.period()
+
+		for ; !period.After(limit); period = rl.next(period) {
+			for _, date := range rl.candidates(period) {
+				if date.Before(rl.start) {
+					continue
+				}
+
+				if date.After(limit) {
+					return
+				}
+
+				if !yield(date) {
+					return
+				}
+
+				if yielded++; rl.count > 0 && yielded == rl.count {
+					return
+				}
+			}
+		}
+	}
+}
+
+// period returns the first day of the period holding the start
+func (rl *rule) period() time.Time {
+	switch rl.frequency {
+	case Weekly:
+		return rl.start.AddDate(0, 0, -mondayOffset(rl.start.Weekday()))
+	case Monthly:
+		return time.Date(rl.start.Year(), rl.start.Month(), 1,
+			0, 0, 0, 0, time.UTC)
+	case Yearly:
+		return time.Date(rl.start.Year(), time.January, 1,
+			0, 0, 0, 0, time.UTC)
+	default:
+		return rl.start
+	}
+}
+
+// next returns the first day of the period after the one of period
+func (rl *rule) next(period time.Time) time.Time {
+	switch rl.frequency {
+	case Weekly:
+		return period.AddDate(0, 0, 7*rl.interval)
+	case Monthly:
+		return period.AddDate(0, rl.interval, 0)
+	case Yearly:
+		return period.AddDate(rl.interval, 0, 0)
+	default:
+		return period.AddDate(0, 0, rl.interval)
+	}
+}
+
+// candidates returns the sorted dates of the period that match the rule
+func (rl *rule) candidates(period time.Time) []time.Time {
+	var dates []time.Time
+
+	switch rl.frequency {
+	case Weekly:
+		if len(rl.byDay) == 0 {
+			dates = append(dates,
+				period.AddDate(0, 0, mondayOffset(rl.start.Weekday())))
+			break
+		}
+
+		for day := range 7 {
+			date := period.AddDate(0, 0, day)
+			if rl.matchesDay(date) {
+				dates = append(dates, date)
+			}
+		}
+	case Monthly:
+		dates = rl.monthDates(period.Year(), period.Month())
+	case Yearly:
+		dates = rl.monthDates(period.Year(), rl.start.Month())
+	default:
+		if rl.matchesDay(period) && rl.matchesMonthDay(period) {
+			dates = append(dates, period)
+		}
+	}
+
+	if len(rl.bySetPos) == 0 {
+		return dates
+	}
+
+	var picked []time.Time
+	for _, pos := range rl.bySetPos {
+		if pos < 0 {
+			pos = len(dates) + pos + 1
+		}
+
+		if pos >= 1 && pos <= len(dates) {
+			picked = append(picked, dates[pos-1])
+		}
+	}
+
+	slices.SortFunc(picked, time.Time.Compare)
+
+	return slices.Compact(picked)
+}
+
+// monthDates returns the dates of the month matching ByMonthDay and ByDay,
+// or the day of the start when neither is set
+func (rl *rule) monthDates(year int, month time.Month) []time.Time {
+	days := daysIn(year, month)
+
+	if len(rl.byDay) == 0 && len(rl.byMonthDay) == 0 {
+		if rl.start.Day() > days {
+			return nil
+		}
+
+		return []time.Time{
+			time.Date(year, month, rl.start.Day(), 0, 0, 0, 0, time.UTC),
+		}
+	}
+
+	var dates []time.Time
+	for day := 1; day <= days; day++ {
+		date := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+		if rl.matchesDay(date) && rl.matchesMonthDay(date) {
+			dates = append(dates, date)
+		}
+	}
+
+	return dates
+}
+
+// matchesDay reports whether date is one of the ByDay weekdays, ordinals
+// counting within the month
+func (rl *rule) matchesDay(date time.Time) bool {
+	if len(rl.byDay) == 0 {
+		return true
+	}
+
+	days := daysIn(date.Year(), date.Month())
+
+	for _, day := range rl.byDay {
+		if day.weekday != date.Weekday() {
+			continue
+		}
+
+		switch {
+		case day.n == 0,
+			day.n > 0 && (date.Day()-1)/7+1 == day.n,
+			day.n < 0 && (days-date.Day())/7+1 == -day.n:
+			return true
+		}
+	}
+
+	return false
+}
+
+// matchesMonthDay reports whether date is one of the ByMonthDay days,
+// negative days counting from the end of the month
+func (rl *rule) matchesMonthDay(date time.Time) bool {
+	if len(rl.byMonthDay) == 0 {
+		return true
+	}
+
+	days := daysIn(date.Year(), date.Month())
+
+	for _, day := range rl.byMonthDay {
+		if day < 0 {
+			day = days + day + 1
+		}
+
+		if day == date.Day() {
+			return true
+		}
+	}
+
+	return false
+}
+
+func daysIn(year int, month time.Month) int {
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+// mondayOffset returns the number of days from Monday to weekday
+func mondayOffset(weekday time.Weekday) int {
+	return (int(weekday) + 6) % 7
+}
+
+func dateOnly(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// Project returns copies of the repeating entry e, without ID, dated at
+// the occurrences of its Repeat between from and to that come after the
+// date of e. e being the latest entry of its series, they are the entries
+// Toshl has not created yet. Entries that do not repeat have none.
+func (e *Entry) Project(from, to Date) ([]Entry, error) {
+	if e.Repeat == nil {
+		return nil, nil
+	}
+
+	after, err := time.Parse(DateFormat, e.Date)
+	if err != nil {
+		return nil, err
+	}
+
+	return e.project(after, from, to)
+}
+
+func (e *Entry) project(after time.Time, from, to Date) ([]Entry, error) {
+	dates, err := e.Repeat.Occurrences(from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	var projected []Entry
+
+	for _, date := range dates {
+		if !time.Time(date).After(after) {
+			continue
+		}
+
+		entry := *e
+		entry.Id = nil
+		entry.Date = date.String()
+		entry.Created = time.Time{}
+		entry.Modified = nil
+		entry.Images = nil
+		entry.Completed = false
+
+		repeat := *e.Repeat
+		repeat.IsTemplate = false
+		repeat.Entries = nil
+		entry.Repeat = &repeat
+
+		projected = append(projected, entry)
+	}
+
+	return projected, nil
+}
+
+// ProjectEntries returns the entries the repeating series among entries
+// will have between from and to, besides those already in entries, sorted
+// by date. Series are told apart by their Repeat ID and projected from
+// their template when entries hold it, from their latest entry otherwise.
+func ProjectEntries(entries []Entry, from, to Date) ([]Entry, error) {
+	type series struct {
+		model  *Entry
+		latest time.Time
+	}
+
+	var order []string
+	byID := map[string]*series{}
+
+	for i := range entries {
+		entry := &entries[i]
+		if entry.Repeat == nil || entry.Deleted {
+			continue
+		}
+
+		date, err := time.Parse(DateFormat, entry.Date)
+		if err != nil {
+			return nil, err
+		}
+
+		id := entry.Repeat.Id
+		if id == "" {
+			id = fmt.Sprintf("entry %d", i)
+		}
+
+		s, ok := byID[id]
+		if !ok {
+			s = &series{}
+			byID[id] = s
+			order = append(order, id)
+		}
+
+		if entry.Repeat.IsTemplate {
+			s.model = entry
+			continue
+		}
+
+		if date.After(s.latest) {
+			s.latest = date
+
+			if s.model == nil || !s.model.Repeat.IsTemplate {
+				s.model = entry
+			}
+		}
+	}
+
+	var projected []Entry
+
+	for _, id := range order {
+		s := byID[id]
+
+		// When only the template is known, latest is zero and every
+		// occurrence is projected
+		series, err := s.model.project(s.latest, from, to)
+		if err != nil {
+			return nil, err
+		}
+
+		projected = append(projected, series...)
+	}
+
+	slices.SortStableFunc(projected, func(a, b Entry) int {
+		return strings.Compare(a.Date, b.Date)
+	})
+
+	return projected, nil
+}
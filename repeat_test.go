@@ -0,0 +1,272 @@
+package toshl_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/Philanthropists/toshl-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func dateStrings(dates []toshl.Date) []string {
+	var s []string
+	for _, date := range dates {
+		s = append(s, date.String())
+	}
+
+	return s
+}
+
+func TestRepeatOccurrences(t *testing.T) {
+	for name, test := range map[string]struct {
+		repeat toshl.Repeat
+		from   toshl.Date
+		to     toshl.Date
+		want   []string
+	}{
+		"daily with interval": {
+			repeat: toshl.Repeat{
+				Frequency: toshl.Daily,
+				Interval:  3,
+				Start:     testDate(2024, time.January, 30),
+			},
+			from: testDate(2024, time.February, 1),
+			to:   testDate(2024, time.February, 10),
+			want: []string{"2024-02-02", "2024-02-05", "2024-02-08"},
+		},
+		"weekly on the start weekday": {
+			repeat: toshl.Repeat{
+				Frequency: toshl.Weekly,
+				Interval:  2,
+				Start:     testDate(2024, time.March, 6),
+			},
+			from: testDate(2024, time.March, 1),
+			to:   testDate(2024, time.April, 5),
+			want: []string{"2024-03-06", "2024-03-20", "2024-04-03"},
+		},
+		"weekly by day": {
+			repeat: toshl.Repeat{
+				Frequency: toshl.Weekly,
+				ByDay:     "MO,fr",
+				Start:     testDate(2024, time.March, 6),
+			},
+			from: testDate(2024, time.March, 1),
+			to:   testDate(2024, time.March, 15),
+			want: []string{"2024-03-08", "2024-03-11", "2024-03-15"},
+		},
+		"monthly skips short months": {
+			repeat: toshl.Repeat{
+				Frequency: toshl.Monthly,
+				Start:     testDate(2024, time.January, 31),
+			},
+			from: testDate(2024, time.January, 1),
+			to:   testDate(2024, time.May, 31),
+			want: []string{"2024-01-31", "2024-03-31", "2024-05-31"},
+		},
+		"monthly on the last day": {
+			repeat: toshl.Repeat{
+				Frequency:  toshl.Monthly,
+				ByMonthDay: "-1",
+				Start:      testDate(2024, time.January, 1),
+			},
+			from: testDate(2024, time.January, 1),
+			to:   testDate(2024, time.March, 31),
+			want: []string{"2024-01-31", "2024-02-29", "2024-03-31"},
+		},
+		"monthly on the last friday": {
+			repeat: toshl.Repeat{
+				Frequency: toshl.Monthly,
+				ByDay:     "-1FR",
+				Start:     testDate(2024, time.January, 1),
+			},
+			from: testDate(2024, time.January, 1),
+			to:   testDate(2024, time.March, 31),
+			want: []string{"2024-01-26", "2024-02-23", "2024-03-29"},
+		},
+		"monthly on the last working day": {
+			repeat: toshl.Repeat{
+				Frequency: toshl.Monthly,
+				ByDay:     "MO,TU,WE,TH,FR",
+				BySetPos:  "-1",
+				Start:     testDate(2024, time.January, 1),
+			},
+			from: testDate(2024, time.January, 1),
+			to:   testDate(2024, time.March, 31),
+			want: []string{"2024-01-31", "2024-02-29", "2024-03-29"},
+		},
+		"yearly on leap days": {
+			repeat: toshl.Repeat{
+				Frequency: toshl.Yearly,
+				Start:     testDate(2024, time.February, 29),
+			},
+			from: testDate(2024, time.January, 1),
+			to:   testDate(2029, time.January, 1),
+			want: []string{"2024-02-29", "2028-02-29"},
+		},
+		"count": {
+			repeat: toshl.Repeat{
+				Frequency: toshl.Monthly,
+				Count:     3,
+				Start:     testDate(2024, time.January, 15),
+			},
+			from: testDate(2024, time.February, 1),
+			to:   testDate(2024, time.December, 31),
+			want: []string{"2024-02-15", "2024-03-15"},
+		},
+		"end": {
+			repeat: toshl.Repeat{
+				Frequency: toshl.Weekly,
+				Start:     testDate(2024, time.January, 1),
+				End:       testDate(2024, time.January, 15),
+			},
+			from: testDate(2024, time.January, 1),
+			to:   testDate(2024, time.December, 31),
+			want: []string{"2024-01-01", "2024-01-08", "2024-01-15"},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			dates, err := test.repeat.Occurrences(test.from, test.to)
+			require.NoError(t, err)
+			assert.Equal(t, test.want, dateStrings(dates))
+		})
+	}
+}
+
+func TestRepeatValidate(t *testing.T) {
+	for name, repeat := range map[string]toshl.Repeat{
+		"frequency": {Frequency: "hourly", Start: testDate(2024, 1, 1)},
+		"start":     {Frequency: toshl.Daily},
+		"end": {
+			Frequency: toshl.Daily,
+			Start:     testDate(2024, 1, 2),
+			End:       testDate(2024, 1, 1),
+		},
+		"byday": {
+			Frequency: toshl.Weekly,
+			ByDay:     "MO,XX",
+			Start:     testDate(2024, 1, 1),
+		},
+		"weekly byday ordinal": {
+			Frequency: toshl.Weekly,
+			ByDay:     "2MO",
+			Start:     testDate(2024, 1, 1),
+		},
+		"bymonthday": {
+			Frequency:  toshl.Monthly,
+			ByMonthDay: "32",
+			Start:      testDate(2024, 1, 1),
+		},
+		"bysetpos alone": {
+			Frequency: toshl.Monthly,
+			BySetPos:  "1",
+			Start:     testDate(2024, 1, 1),
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			assert.Error(t, repeat.Validate())
+
+			_, err := repeat.Occurrences(testDate(2024, 1, 1),
+				testDate(2024, 12, 31))
+			assert.Error(t, err)
+		})
+	}
+
+	valid := toshl.Repeat{
+		Frequency:  toshl.Monthly,
+		ByMonthDay: "1, 15",
+		Start:      testDate(2024, 1, 1),
+	}
+	assert.NoError(t, valid.Validate())
+}
+
+func TestCreateEntryInvalidRepeat(t *testing.T) {
+	client, requests := newTestClient(t, http.StatusCreated, "")
+
+	err := client.CreateEntry(&toshl.Entry{
+		Amount:   toshl.NewAmount(-1000, 0),
+		Currency: toshl.Currency{Code: "EUR"},
+		Date:     "2024-01-01",
+		Account:  "1",
+		Repeat:   &toshl.Repeat{Frequency: toshl.Monthly},
+	})
+	assert.Error(t, err)
+	assert.Empty(t, *requests)
+}
+
+func TestEntryProject(t *testing.T) {
+	id := "7"
+	rent := toshl.Entry{
+		Id:       &id,
+		Amount:   toshl.NewAmount(-1000, 0),
+		Currency: toshl.Currency{Code: "EUR"},
+		Date:     "2024-02-01",
+		Account:  "1",
+		Repeat: &toshl.Repeat{
+			Id:        "r1",
+			Frequency: toshl.Monthly,
+			Start:     testDate(2024, time.January, 1),
+			Entries:   []string{"6", "7"},
+		},
+	}
+
+	projected, err := rent.Project(testDate(2024, time.January, 1),
+		testDate(2024, time.April, 30))
+	require.NoError(t, err)
+	require.Len(t, projected, 2)
+
+	assert.Equal(t, "2024-02-01", rent.Date)
+	assert.Equal(t, "2024-03-01", projected[0].Date)
+	assert.Equal(t, "2024-04-01", projected[1].Date)
+	assert.Nil(t, projected[0].Id)
+	assert.Nil(t, projected[0].Repeat.Entries)
+	assert.True(t, projected[0].Amount.Equal(rent.Amount))
+
+	once := toshl.Entry{Date: "2024-02-01"}
+	projected, err = once.Project(testDate(2024, time.January, 1),
+		testDate(2024, time.April, 30))
+	assert.NoError(t, err)
+	assert.Empty(t, projected)
+}
+
+func TestProjectEntries(t *testing.T) {
+	salary := &toshl.Repeat{
+		Id:         "salary",
+		Frequency:  toshl.Monthly,
+		ByMonthDay: "25",
+		Start:      testDate(2024, time.January, 25),
+	}
+
+	template := *salary
+	template.IsTemplate = true
+
+	rent := &toshl.Repeat{
+		Frequency: toshl.Weekly,
+		Interval:  2,
+		Start:     testDate(2024, time.March, 4),
+	}
+
+	projected, err := toshl.ProjectEntries([]toshl.Entry{
+		{Date: "2024-02-25", Amount: toshl.NewAmount(2000, 0), Repeat: salary},
+		{Date: "2024-03-25", Amount: toshl.NewAmount(2000, 0), Repeat: salary},
+		{Date: "2024-01-25", Amount: toshl.NewAmount(2500, 0),
+			Repeat: &template},
+		{Date: "2024-03-04", Amount: toshl.NewAmount(-500, 0), Repeat: rent},
+		{Date: "2024-03-10", Amount: toshl.NewAmount(-30, 0)},
+	}, testDate(2024, time.March, 1), testDate(2024, time.April, 30))
+	require.NoError(t, err)
+
+	var got []string
+	for _, entry := range projected {
+		got = append(got, entry.Date+" "+entry.Amount.String())
+	}
+
+	assert.Equal(t, []string{
+		"2024-03-18 -500",
+		"2024-04-01 -500",
+		"2024-04-15 -500",
+		"2024-04-25 2500",
+		"2024-04-29 -500",
+	}, got)
+}
@@ -0,0 +1,126 @@
+package toshl
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy describes how RestHTTPClient retries failed requests.
+// GET, PUT and DELETE requests are retried on transport errors, 429 and
+// 5XX responses. POST requests are only retried when RetryPost is set, and
+// then only when Toshl cannot have created anything: rate limited
+// responses and connections that failed before the request was sent.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first
+	MaxAttempts int
+
+	// InitialBackoff is the delay before the first retry, doubled on every
+	// following attempt up to MaxBackoff. A random jitter of up to half
+	// the delay is subtracted from it.
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+
+	RetryPost bool
+}
+
+// DefaultRetryPolicy is a sensible RetryPolicy for most callers
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts:    4,
+	InitialBackoff: 500 * time.Millisecond,
+	MaxBackoff:     30 * time.Second,
+}
+
+func (p *RetryPolicy) shouldRetry(
+	ctx context.Context, method string, attempt int, err error,
+) bool {
+	if p == nil || attempt >= p.MaxAttempts || ctx.Err() != nil {
+		return false
+	}
+
+	if errors.Is(err, ErrRateLimited) {
+		return false
+	}
+
+	idempotent := method != http.MethodPost
+
+	if apiErr, ok := asAPIError(err); ok {
+		switch {
+		case apiErr.StatusCode == http.StatusTooManyRequests:
+			return idempotent || p.RetryPost
+		case apiErr.StatusCode >= 500:
+			return idempotent
+		}
+
+		return false
+	}
+
+	return idempotent || (p.RetryPost && isNotSent(err))
+}
+
+// isNotSent reports whether err happened before the request could reach
+// the server
+func isNotSent(err error) bool {
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "dial" {
+		return true
+	}
+
+	var dnsErr *net.DNSError
+	return errors.As(err, &dnsErr)
+}
+
+func (p *RetryPolicy) backoff(attempt int, resp *http.Response) time.Duration {
+	if resp != nil {
+		retryAfter := parseRetryAfter(resp.Header.Get("Retry-After"))
+		if retryAfter > 0 {
+			return retryAfter
+		}
+	}
+
+	delay := p.InitialBackoff << (attempt - 1)
+	if delay <= 0 || (p.MaxBackoff > 0 && delay > p.MaxBackoff) {
+		delay = p.MaxBackoff
+	}
+
+	if delay <= 0 {
+		return 0
+	}
+
+	return delay - rand.N(delay/2+1)
+}
+
+// parseRetryAfter parses a Retry-After header given either in seconds or
+// as an HTTP date
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		return max(time.Until(date), 0)
+	}
+
+	return 0
+}
+
+// sleep waits for the given duration or until ctx is done
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,135 @@
+package toshl
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newFlakyServer(failures, status int, requests *int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			*requests++
+			if *requests <= failures {
+				w.WriteHeader(status)
+				return
+			}
+
+			w.Header().Set("Location", "https://api.toshl.com/entries/42")
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`[]`))
+		}))
+}
+
+var testRetryPolicy = RetryPolicy{
+	MaxAttempts:    3,
+	InitialBackoff: time.Millisecond,
+	MaxBackoff:     5 * time.Millisecond,
+}
+
+func TestRestHTTPClientRetriesIdempotentRequests(t *testing.T) {
+	requests := 0
+	server := newFlakyServer(2, http.StatusServiceUnavailable, &requests)
+	defer server.Close()
+
+	c := &RestHTTPClient{
+		BaseURL:     server.URL,
+		Client:      server.Client(),
+		RetryPolicy: &testRetryPolicy,
+	}
+
+	res, err := c.Get("entries", "")
+	assert.Nil(t, err)
+	assert.Equal(t, "[]", res)
+	assert.Equal(t, 3, requests)
+}
+
+func TestRestHTTPClientGivesUpAfterMaxAttempts(t *testing.T) {
+	requests := 0
+	server := newFlakyServer(5, http.StatusBadGateway, &requests)
+	defer server.Close()
+
+	c := &RestHTTPClient{
+		BaseURL:     server.URL,
+		Client:      server.Client(),
+		RetryPolicy: &testRetryPolicy,
+	}
+
+	err := c.Delete("entries/42")
+	assert.NotNil(t, err)
+	assert.Equal(t, 3, requests)
+}
+
+func TestRestHTTPClientDoesNotRetryPostOnServerError(t *testing.T) {
+	requests := 0
+	server := newFlakyServer(1, http.StatusInternalServerError, &requests)
+	defer server.Close()
+
+	policy := testRetryPolicy
+	policy.RetryPost = true
+
+	c := &RestHTTPClient{
+		BaseURL:     server.URL,
+		Client:      server.Client(),
+		RetryPolicy: &policy,
+	}
+
+	_, err := c.Post("entries", "{}")
+	assert.NotNil(t, err)
+	assert.Equal(t, 1, requests)
+}
+
+func TestRestHTTPClientRetriesRateLimitedPost(t *testing.T) {
+	requests := 0
+	server := newFlakyServer(1, http.StatusTooManyRequests, &requests)
+	defer server.Close()
+
+	c := &RestHTTPClient{
+		BaseURL:     server.URL,
+		Client:      server.Client(),
+		RetryPolicy: &testRetryPolicy,
+	}
+
+	_, err := c.Post("entries", "{}")
+	assert.True(t, IsRateLimited(err))
+	assert.Equal(t, 1, requests)
+
+	policy := testRetryPolicy
+	policy.RetryPost = true
+	c.RetryPolicy = &policy
+	requests = 0
+
+	id, err := c.Post("entries", "{}")
+	assert.Nil(t, err)
+	assert.Equal(t, "42", id)
+	assert.Equal(t, 2, requests)
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	p := RetryPolicy{InitialBackoff: time.Second, MaxBackoff: 4 * time.Second}
+
+	for attempt, limit := range map[int]time.Duration{
+		1: time.Second,
+		2: 2 * time.Second,
+		3: 4 * time.Second,
+		6: 4 * time.Second,
+	} {
+		delay := p.backoff(attempt, nil)
+		assert.LessOrEqual(t, delay, limit)
+		assert.GreaterOrEqual(t, delay, limit/2)
+	}
+
+	resp := &http.Response{Header: http.Header{"Retry-After": {"7"}}}
+	assert.Equal(t, 7*time.Second, p.backoff(1, resp))
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	assert.Equal(t, 120*time.Second, parseRetryAfter("120"))
+	assert.Equal(t, time.Duration(0), parseRetryAfter(""))
+	assert.Equal(t, time.Duration(0), parseRetryAfter("soon"))
+	assert.Equal(t, time.Duration(0),
+		parseRetryAfter("Wed, 21 Oct 2015 07:28:00 GMT"))
+}
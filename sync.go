@@ -0,0 +1,294 @@
+package toshl
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"maps"
+	"time"
+)
+
+// SyncResource identifies a resource type kept in sync by a Syncer
+type SyncResource string
+
+const (
+	SyncAccounts   SyncResource = "accounts"
+	SyncCategories SyncResource = "categories"
+	SyncTags       SyncResource = "tags"
+	SyncBudgets    SyncResource = "budgets"
+	SyncEntries    SyncResource = "entries"
+)
+
+// syncResources lists every resource type, the referenced ones first
+var syncResources = []SyncResource{
+	SyncAccounts, SyncCategories, SyncTags, SyncBudgets, SyncEntries,
+}
+
+// ChangeType represents the kind of a Change
+type ChangeType string
+
+const (
+	ChangeCreated ChangeType = "created"
+	ChangeUpdated ChangeType = "updated"
+	ChangeDeleted ChangeType = "deleted"
+)
+
+// Change represents a change of a single object pulled by a Syncer
+type Change struct {
+	Resource SyncResource
+	Type     ChangeType
+	ID       string
+
+	// Modified is the modification time of the object, zero when the API
+	// returned none or an unparsable one
+	Modified time.Time
+
+	// Object is the changed object as returned by the API: an *Account,
+	// *Category, *Tag, *Budget or *Entry depending on Resource
+	Object any
+}
+
+// SyncStore persists what a Syncer pulls. Its methods are called
+// sequentially.
+type SyncStore interface {
+	// Cursor returns the cursor saved for resource, the zero time if none
+	Cursor(ctx context.Context, resource SyncResource) (time.Time, error)
+
+	// SaveCursor saves the cursor of resource once its changes are applied
+	SaveCursor(
+		ctx context.Context, resource SyncResource, cursor time.Time,
+	) error
+
+	// Has reports whether the store holds the object, telling created
+	// objects apart from updated ones
+	Has(ctx context.Context, resource SyncResource, id string) (bool, error)
+
+	// Apply applies a change. Changes made at the cursor may be applied
+	// again by a new Syncer or after an interrupted sync, so Apply should
+	// be idempotent.
+	Apply(ctx context.Context, change Change) error
+}
+
+// Syncer mirrors Toshl resources into a SyncStore, pulling only the
+// objects modified or deleted since the previous sync
+type Syncer struct {
+	client    *Client
+	store     SyncStore
+	resources []SyncResource
+
+	// applied holds, by resource, the IDs of the objects this Syncer
+	// applied at the saved cursor
+	applied map[SyncResource]appliedAt
+}
+
+// appliedAt holds the IDs of the objects applied at a cursor
+type appliedAt struct {
+	cursor time.Time
+	ids    map[string]bool
+}
+
+// NewSyncer returns a Syncer pulling resources, or every resource type
+// when none is given
+func NewSyncer(
+	client *Client, store SyncStore, resources ...SyncResource,
+) *Syncer {
+	if len(resources) == 0 {
+		resources = syncResources
+	}
+
+	return &Syncer{
+		client:    client,
+		store:     store,
+		resources: resources,
+		applied:   map[SyncResource]appliedAt{},
+	}
+}
+
+// Sync pulls the changes of every resource type of the Syncer
+func (s *Syncer) Sync(ctx context.Context) error {
+	for _, resource := range s.resources {
+		if err := s.SyncResource(ctx, resource); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// SyncResource pulls the changes of a single resource type. The cursor is
+// only saved once every change has been applied. Objects modified at the
+// cursor are applied again, unless this Syncer already applied them.
+func (s *Syncer) SyncResource(ctx context.Context, resource SyncResource) error {
+	cursor, err := s.store.Cursor(ctx, resource)
+	if err != nil {
+		return err
+	}
+
+	// The API only takes whole seconds, so objects already applied before
+	// the cursor come back and are skipped below
+	since := cursor.UTC().Truncate(time.Second)
+	if cursor.IsZero() {
+		// Entries cannot be listed without either a date range or since
+		since = time.Unix(0, 0).UTC()
+	}
+
+	latest := cursor
+
+	var atCursor map[string]bool
+	if applied := s.applied[resource]; applied.cursor.Equal(cursor) {
+		atCursor = applied.ids
+	}
+
+	atLatest := maps.Clone(atCursor)
+	if atLatest == nil {
+		atLatest = map[string]bool{}
+	}
+
+	for item, err := range s.pull(ctx, resource, since) {
+		if err != nil {
+			return err
+		}
+
+		modified, _ := parseModified(item.modified)
+		if !modified.IsZero() && (modified.Before(cursor) ||
+			modified.Equal(cursor) && atCursor[item.id]) {
+			continue
+		}
+
+		change := Change{
+			Resource: resource,
+			ID:       item.id,
+			Modified: modified,
+			Object:   item.object,
+		}
+
+		known, err := s.store.Has(ctx, resource, item.id)
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case item.deleted:
+			change.Type = ChangeDeleted
+		case known:
+			change.Type = ChangeUpdated
+		default:
+			change.Type = ChangeCreated
+		}
+
+		// Objects deleted before ever being synced have nothing to delete
+		if known || !item.deleted {
+			if err := s.store.Apply(ctx, change); err != nil {
+				return err
+			}
+		}
+
+		switch {
+		case modified.After(latest):
+			latest = modified
+			atLatest = map[string]bool{item.id: true}
+		case modified.Equal(latest):
+			atLatest[item.id] = true
+		}
+	}
+
+	if !latest.Equal(cursor) {
+		if err := s.store.SaveCursor(ctx, resource, latest); err != nil {
+			return err
+		}
+	}
+
+	s.applied[resource] = appliedAt{cursor: latest, ids: atLatest}
+
+	return nil
+}
+
+// syncItem holds what a Syncer needs to know about a pulled object
+type syncItem struct {
+	id       string
+	modified string
+	deleted  bool
+	object   any
+}
+
+func (s *Syncer) pull(
+	ctx context.Context, resource SyncResource, since time.Time,
+) iter.Seq2[syncItem, error] {
+	c := s.client
+
+	switch resource {
+	case SyncAccounts:
+		params := &AccountQueryParams{Since: since, IncludeDeleted: true}
+		return syncItems(c.AllAccounts(ctx, params), func(a *Account) syncItem {
+			return syncItem{
+				stringValue(a.ID), stringValue(a.Modified), a.Deleted, a,
+			}
+		})
+	case SyncCategories:
+		params := &CategoryQueryParams{Since: since, IncludeDeleted: true}
+		categories := c.AllCategories(ctx, params)
+		return syncItems(categories, func(cat *Category) syncItem {
+			return syncItem{cat.ID, cat.modified, cat.Deleted, cat}
+		})
+	case SyncTags:
+		params := &TagQueryParams{Since: since, IncludeDeleted: true}
+		return syncItems(c.AllTags(ctx, params), func(t *Tag) syncItem {
+			return syncItem{t.ID, t.Modified, t.Deleted, t}
+		})
+	case SyncBudgets:
+		params := &BudgetQueryParams{Since: since, IncludeDeleted: true}
+		return syncItems(c.AllBudgets(ctx, params), func(b *Budget) syncItem {
+			return syncItem{b.ID, b.Modified, b.Deleted, b}
+		})
+	case SyncEntries:
+		params := &EntryQueryParams{Since: since, IncludeDeleted: true}
+		return syncItems(c.AllEntries(ctx, params), func(e *Entry) syncItem {
+			return syncItem{
+				stringValue(e.Id), stringValue(e.Modified), e.Deleted, e,
+			}
+		})
+	}
+
+	return failed[syncItem](
+		fmt.Errorf("toshl: unknown sync resource %q", resource))
+}
+
+// syncItems maps an iterator over objects to an iterator over syncItems
+func syncItems[T any](
+	seq iter.Seq2[T, error], item func(*T) syncItem,
+) iter.Seq2[syncItem, error] {
+	return func(yield func(syncItem, error) bool) {
+		for v, err := range seq {
+			if err != nil {
+				yield(syncItem{}, err)
+				return
+			}
+
+			if !yield(item(&v), nil) {
+				return
+			}
+		}
+	}
+}
+
+// modifiedLayouts lists the formats of modified timestamps seen in the API
+var modifiedLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.999999999Z0700",
+	"2006-01-02 15:04:05.999999999",
+}
+
+// parseModified parses a modified timestamp leniently, timestamps without
+// a time zone being UTC
+func parseModified(s string) (time.Time, error) {
+	var err error
+
+	for _, layout := range modifiedLayouts {
+		var t time.Time
+		if t, err = time.Parse(layout, s); err == nil {
+			return t.UTC(), nil
+		}
+	}
+
+	return time.Time{}, err
+}
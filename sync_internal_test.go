@@ -0,0 +1,28 @@
+package toshl
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseModified(t *testing.T) {
+	want := time.Date(2024, time.March, 5, 10, 30, 15, 0, time.UTC)
+
+	for _, s := range []string{
+		"2024-03-05T10:30:15Z",
+		"2024-03-05T10:30:15.000Z",
+		"2024-03-05T11:30:15+01:00",
+		"2024-03-05T11:30:15+0100",
+		"2024-03-05 10:30:15",
+	} {
+		got, err := parseModified(s)
+		if assert.NoError(t, err, s) {
+			assert.True(t, want.Equal(got), "%s: got %v", s, got)
+		}
+	}
+
+	_, err := parseModified("yesterday")
+	assert.Error(t, err)
+}
@@ -0,0 +1,161 @@
+package toshl_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Philanthropists/toshl-go"
+	"github.com/Philanthropists/toshl-go/toshltest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryStore is a SyncStore recording the applied changes
+type memoryStore struct {
+	cursors map[toshl.SyncResource]time.Time
+	objects map[string]bool
+	changes []toshl.Change
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{
+		cursors: map[toshl.SyncResource]time.Time{},
+		objects: map[string]bool{},
+	}
+}
+
+func (m *memoryStore) Cursor(
+	_ context.Context, resource toshl.SyncResource,
+) (time.Time, error) {
+	return m.cursors[resource], nil
+}
+
+func (m *memoryStore) SaveCursor(
+	_ context.Context, resource toshl.SyncResource, cursor time.Time,
+) error {
+	m.cursors[resource] = cursor
+	return nil
+}
+
+func (m *memoryStore) Has(
+	_ context.Context, resource toshl.SyncResource, id string,
+) (bool, error) {
+	return m.objects[string(resource)+"/"+id], nil
+}
+
+func (m *memoryStore) Apply(_ context.Context, change toshl.Change) error {
+	key := string(change.Resource) + "/" + change.ID
+	m.objects[key] = change.Type != toshl.ChangeDeleted
+	m.changes = append(m.changes, change)
+	return nil
+}
+
+// drain returns the changes applied since the last call as "type id"
+func (m *memoryStore) drain() []string {
+	var changes []string
+	for _, change := range m.changes {
+		changes = append(changes, string(change.Type)+" "+change.ID)
+	}
+
+	m.changes = nil
+
+	return changes
+}
+
+func TestSyncer(t *testing.T) {
+	server := toshltest.NewServer()
+	defer server.Close()
+
+	client := server.NewClient()
+	store := newMemoryStore()
+	syncer := toshl.NewSyncer(client, store, toshl.SyncAccounts)
+	ctx := context.Background()
+
+	var ids []string
+	for _, name := range []string{"Checking", "Savings", "Cash"} {
+		id, err := client.CreateAccount(toshl.CreateAccountParams{
+			Name:     name,
+			Currency: toshl.Currency{Code: "USD"},
+		})
+		require.NoError(t, err)
+
+		ids = append(ids, id)
+	}
+
+	require.NoError(t, syncer.Sync(ctx))
+	assert.Equal(t, []string{
+		"created " + ids[0], "created " + ids[1], "created " + ids[2],
+	}, store.drain())
+	assert.False(t, store.cursors[toshl.SyncAccounts].IsZero())
+
+	account, err := client.GetAccount(ids[0])
+	require.NoError(t, err)
+
+	account.Name = "Main"
+	require.NoError(t, client.UpdateAccount(account))
+
+	account, err = client.GetAccount(ids[1])
+	require.NoError(t, err)
+	require.NoError(t, client.DeleteAccount(account))
+
+	require.NoError(t, syncer.Sync(ctx))
+	assert.Equal(t, []string{
+		"updated " + ids[0], "deleted " + ids[1],
+	}, store.drain())
+
+	require.NoError(t, syncer.Sync(ctx))
+	assert.Empty(t, store.drain())
+}
+
+func TestSyncerSkipsUnknownDeletions(t *testing.T) {
+	server := toshltest.NewServer()
+	defer server.Close()
+
+	client := server.NewClient()
+	store := newMemoryStore()
+
+	category := &toshl.Category{Name: "Food", Type: "expense"}
+	require.NoError(t, client.CreateCategory(category))
+	require.NoError(t, client.DeleteCategory(category))
+
+	syncer := toshl.NewSyncer(client, store, toshl.SyncCategories)
+	require.NoError(t, syncer.Sync(context.Background()))
+
+	assert.Empty(t, store.drain())
+}
+
+func TestSyncerAppliesObjectsAtCursor(t *testing.T) {
+	server := toshltest.NewServer()
+	defer server.Close()
+
+	client := server.NewClient()
+
+	var ids []string
+	for _, name := range []string{"Checking", "Savings"} {
+		id, err := client.CreateAccount(toshl.CreateAccountParams{
+			Name:     name,
+			Currency: toshl.Currency{Code: "USD"},
+		})
+		require.NoError(t, err)
+
+		ids = append(ids, id)
+	}
+
+	account, err := client.GetAccount(ids[1])
+	require.NoError(t, err)
+
+	modified, err := time.Parse(time.RFC3339, *account.Modified)
+	require.NoError(t, err)
+
+	// Another object modified at the same time was the last one synced
+	store := newMemoryStore()
+	store.cursors[toshl.SyncAccounts] = modified
+
+	syncer := toshl.NewSyncer(client, store, toshl.SyncAccounts)
+	require.NoError(t, syncer.Sync(context.Background()))
+	assert.Equal(t, []string{"created " + ids[1]}, store.drain())
+
+	require.NoError(t, syncer.Sync(context.Background()))
+	assert.Empty(t, store.drain())
+}
@@ -0,0 +1,76 @@
+package toshl
+
+import (
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// Tag represents a Toshl tag
+type Tag struct {
+	ID       string    `json:"id"`
+	Name     string    `json:"name"`
+	Modified string    `json:"modified,omitempty"`
+	Type     string    `json:"type"`
+	Deleted  bool      `json:"deleted"`
+	Category string    `json:"category,omitempty"`
+	Counts   TagCounts `json:"counts"`
+}
+
+// TagCounts represents the number of objects using a Toshl tag
+type TagCounts struct {
+	Entries int `json:"entries"`
+	Budgets int `json:"budgets"`
+}
+
+// TagQueryParams represents a struct of parameters usable
+// to List Tags
+type TagQueryParams struct {
+	Page           int
+	PerPage        int
+	Since          time.Time
+	Type           string
+	Category       string
+	Search         string
+	IncludeDeleted bool
+}
+
+func (t *TagQueryParams) getQueryString() string {
+	v := url.Values{}
+
+	if t.Page > 0 {
+		v.Set("page", strconv.Itoa(t.Page))
+	}
+
+	if t.PerPage > 0 {
+		v.Set("per_page", strconv.Itoa(t.PerPage))
+	}
+
+	if !t.Since.IsZero() {
+		v.Set("since", t.Since.Format(sinceFormat))
+	}
+
+	if t.Type != "" {
+		v.Set("type", t.Type)
+	}
+
+	if t.Category != "" {
+		v.Set("category", t.Category)
+	}
+
+	if t.Search != "" {
+		v.Set("search", t.Search)
+	}
+
+	if t.IncludeDeleted {
+		v.Set("include_deleted", strconv.FormatBool(t.IncludeDeleted))
+	}
+
+	return v.Encode()
+}
+
+// TagsMergeParams describes how we want to merge the tags
+type TagsMergeParams struct {
+	Tags []string `json:"tags"`
+	Tag  string   `json:"tag"`
+}
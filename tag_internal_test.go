@@ -0,0 +1,26 @@
+package toshl
+
+import (
+	"testing"
+
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTagGetQueryString(t *testing.T) {
+	tag := TagQueryParams{
+		Page:           2,
+		PerPage:        1,
+		Since:          time.Date(2016, 11, 6, 13, 28, 0, 0, time.Local),
+		Type:           "expense",
+		Category:       "cat1",
+		Search:         "search_term",
+		IncludeDeleted: true,
+	}
+
+	assert.Equal(t,
+		tag.getQueryString(),
+		`category=cat1&include_deleted=true&page=2&per_page=1&`+
+			`search=search_term&since=2016-11-06T13%3A28%3A00Z&type=expense`)
+}
@@ -0,0 +1,56 @@
+package toshl_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/Philanthropists/toshl-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTagDecode(t *testing.T) {
+	var tag toshl.Tag
+	tagJSON := []byte(`{
+        "id": "42",
+        "name": "ice cream",
+        "modified": "2012-09-04T13:55:15Z",
+        "type": "expense",
+        "deleted": false,
+        "category": "7",
+        "counts": {
+            "entries": 12,
+            "budgets": 1
+        }
+    }`)
+
+	err := json.Unmarshal(tagJSON, &tag)
+	assert.Nil(t, err)
+	assert.Equal(t, "7", tag.Category)
+	assert.Equal(t, 12, tag.Counts.Entries)
+}
+
+func TestClientCreateTag(t *testing.T) {
+	c, requests := newTestClient(t, http.StatusCreated, "")
+
+	tag := &toshl.Tag{Name: "ice cream", Type: "expense"}
+
+	err := c.CreateTag(tag)
+	assert.Nil(t, err)
+	assert.Equal(t, "42", tag.ID)
+	assert.Equal(t, "POST", (*requests)[0].Method)
+	assert.Equal(t, "/tags", (*requests)[0].Path)
+}
+
+func TestClientMergeTags(t *testing.T) {
+	c, requests := newTestClient(t, http.StatusNoContent, "")
+
+	err := c.MergeTags(&toshl.TagsMergeParams{
+		Tags: []string{"1", "2"},
+		Tag:  "3",
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, "/tags/merge", (*requests)[0].Path)
+	assert.JSONEq(t,
+		`{"tags": ["1", "2"], "tag": "3"}`, (*requests)[0].Body)
+}
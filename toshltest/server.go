@@ -0,0 +1,754 @@
+// Package toshltest provides an in-memory fake of the Toshl API for
+// integration tests.
+//
+// The fake keeps accounts, categories, tags, budgets and entries as plain
+// JSON objects, so it serves whatever fields the client sends. It supports
+// listing with Link header pagination, since and include_deleted filters,
+// creation with a Location header, updates rejecting stale modified values,
+// deletions, reordering and merging, and answers invalid requests with
+// Toshl style validation errors. Images can be uploaded and downloaded.
+package toshltest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Philanthropists/toshl-go"
+)
+
+// DefaultPerPage is the page size used when a request does not set one
+const DefaultPerPage = 200
+
+// maxPerPage is the largest page size accepted by the API
+const maxPerPage = 500
+
+// modifiedFormat is the format of the modified timestamps
+const modifiedFormat = "2006-01-02T15:04:05.000Z"
+
+// object is a resource as stored by the fake
+type object map[string]any
+
+func (o object) id() string {
+	id, _ := o["id"].(string)
+	return id
+}
+
+func (o object) deleted() bool {
+	deleted, _ := o["deleted"].(bool)
+	return deleted
+}
+
+func (o object) str(key string) string {
+	value, _ := o[key].(string)
+	return value
+}
+
+// resource describes a collection served by the fake
+type resource struct {
+	// singular is the name of a single object, used by merge payloads
+	singular string
+
+	// required lists the fields that must be set on creation
+	required []string
+
+	// mergeKey is the entry field referencing this resource, for merges
+	mergeKey string
+
+	// ordered resources are listed by their order field
+	ordered bool
+}
+
+var resources = map[string]resource{
+	"accounts": {
+		singular: "account",
+		required: []string{"name", "currency"},
+		mergeKey: "account",
+		ordered:  true,
+	},
+	"categories": {
+		singular: "category",
+		required: []string{"name", "type"},
+		mergeKey: "category",
+	},
+	"tags": {
+		singular: "tag",
+		required: []string{"name", "type"},
+		mergeKey: "tags",
+	},
+	"budgets": {
+		singular: "budget",
+		required: []string{"name", "limit"},
+		ordered:  true,
+	},
+	"entries": {
+		singular: "entry",
+		required: []string{"amount", "currency", "date", "account"},
+	},
+}
+
+// Server is a fake Toshl API server
+type Server struct {
+	*httptest.Server
+
+	// Token, when set, is the only bearer token accepted
+	Token string
+
+	mu      sync.Mutex
+	objects map[string][]object
+	files   map[string][]byte
+	nextID  int
+	now     func() time.Time
+	last    time.Time
+}
+
+// NewServer starts and returns a new fake Toshl API server. The caller
+// should call Close when finished.
+func NewServer() *Server {
+	s := &Server{
+		objects: map[string][]object{},
+		files:   map[string][]byte{},
+		now:     time.Now,
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+
+	return s
+}
+
+// HTTPClient returns a RestHTTPClient sending requests to the server
+func (s *Server) HTTPClient() *toshl.RestHTTPClient {
+	return &toshl.RestHTTPClient{
+		BaseURL: s.URL,
+		Token:   s.Token,
+		Client:  s.Server.Client(),
+	}
+}
+
+// NewClient returns a Client sending requests to the server
+func (s *Server) NewClient(opts ...toshl.ClientOption) *toshl.Client {
+	return toshl.NewClient(s.Token, s.HTTPClient(), opts...)
+}
+
+// Add stores v, any value encoding to a JSON object, in the collection
+// named resource as if it had been created through the API, and returns
+// its ID
+func (s *Server) Add(resource string, v any) (string, error) {
+	if _, ok := resources[resource]; !ok {
+		return "", fmt.Errorf("toshltest: unknown resource %q", resource)
+	}
+
+	obj, err := toObject(v)
+	if err != nil {
+		return "", err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.create(resource, obj), nil
+}
+
+// Count returns the number of objects of resource not deleted
+func (s *Server) Count(resource string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	count := 0
+	for _, obj := range s.objects[resource] {
+		if !obj.deleted() {
+			count++
+		}
+	}
+
+	return count
+}
+
+func toObject(v any) (object, error) {
+	bs, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var obj object
+	if err := json.Unmarshal(bs, &obj); err != nil {
+		return nil, err
+	}
+
+	return obj, nil
+}
+
+// modified returns a new modified timestamp, s.mu being held. Timestamps
+// strictly increase so that clients syncing with since see every change.
+func (s *Server) modified() string {
+	now := s.now().UTC().Truncate(time.Millisecond)
+	if !now.After(s.last) {
+		now = s.last.Add(time.Millisecond)
+	}
+
+	s.last = now
+
+	return now.Format(modifiedFormat)
+}
+
+// create stores obj with a new ID, s.mu being held
+func (s *Server) create(resource string, obj object) string {
+	s.nextID++
+	id := strconv.Itoa(s.nextID)
+
+	obj["id"] = id
+	obj["modified"] = s.modified()
+	obj["deleted"] = false
+
+	if resource == "entries" {
+		obj["created"] = s.now().UTC().Format(time.RFC3339)
+		s.updateSplit(obj, id, true)
+	}
+
+	if resources[resource].ordered {
+		obj["order"] = len(s.objects[resource])
+	}
+
+	s.objects[resource] = append(s.objects[resource], obj)
+
+	return id
+}
+
+// find returns the object of resource with the given ID, s.mu being held
+func (s *Server) find(resource, id string) object {
+	for _, obj := range s.objects[resource] {
+		if obj.id() == id {
+			return obj
+		}
+	}
+
+	return nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(
+	w http.ResponseWriter, status int, errorID, description string,
+	fields ...toshl.FieldError,
+) {
+	writeJSON(w, status, toshl.APIError{
+		ErrorID:     errorID,
+		Description: description,
+		Fields:      fields,
+	})
+}
+
+func writeNotFound(w http.ResponseWriter, resource, id string) {
+	writeError(w, http.StatusNotFound, "error.object.not_found",
+		fmt.Sprintf("Object with id %s not found in %s.", id, resource))
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	if s.Token != "" && r.Header.Get("Authorization") != "Bearer "+s.Token {
+		writeError(w, http.StatusUnauthorized, "error.auth.invalid_token",
+			"Invalid access token.")
+		return
+	}
+
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+
+	if parts[0] == "images" {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		s.serveImages(w, r, parts)
+		return
+	}
+
+	resource := parts[0]
+	if _, ok := resources[resource]; !ok || len(parts) > 2 {
+		writeError(w, http.StatusNotFound, "error.route.not_found",
+			fmt.Sprintf("Route %s not found.", r.URL.Path))
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch {
+	case len(parts) == 1 && r.Method == http.MethodGet:
+		s.list(w, r, resource)
+	case len(parts) == 1 && r.Method == http.MethodPost:
+		s.post(w, r, resource)
+	case len(parts) == 2 && parts[1] == "reorder" &&
+		r.Method == http.MethodPost && resources[resource].ordered:
+		s.reorder(w, r, resource)
+	case len(parts) == 2 && parts[1] == "merge" &&
+		r.Method == http.MethodPost && resources[resource].mergeKey != "":
+		s.merge(w, r, resource)
+	case len(parts) == 2 && r.Method == http.MethodGet:
+		s.get(w, resource, parts[1])
+	case len(parts) == 2 && r.Method == http.MethodPut:
+		s.put(w, r, resource, parts[1])
+	case len(parts) == 2 && r.Method == http.MethodDelete:
+		s.delete(w, resource, parts[1])
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "error.route.not_allowed",
+			fmt.Sprintf("Method %s not allowed.", r.Method))
+	}
+}
+
+// matches reports whether obj passes the filters of the list query
+func matches(resource string, obj object, query url.Values) bool {
+	if obj.deleted() && query.Get("include_deleted") != "true" {
+		return false
+	}
+
+	if since := query.Get("since"); since != "" {
+		sinceTime, err := time.Parse(time.RFC3339, since)
+		modified, modErr := time.Parse(time.RFC3339, obj.str("modified"))
+		if err == nil && modErr == nil && !modified.After(sinceTime) {
+			return false
+		}
+	}
+
+	if resource != "entries" {
+		return true
+	}
+
+	date := obj.str("date")
+	if from := query.Get("from"); from != "" && date < from {
+		return false
+	}
+
+	if to := query.Get("to"); to != "" && date > to {
+		return false
+	}
+
+	for param, key := range map[string]string{
+		"accounts":   "account",
+		"categories": "category",
+	} {
+		if ids := query.Get(param); ids != "" &&
+			!contains(strings.Split(ids, ","), obj.str(key)) {
+			return false
+		}
+	}
+
+	if parent := query.Get("parent"); parent != "" &&
+		splitParent(obj) != parent {
+		return false
+	}
+
+	if ids := query.Get("tags"); ids != "" {
+		tags, _ := obj["tags"].([]any)
+
+		found := false
+		for _, tag := range tags {
+			tagID, _ := tag.(string)
+			found = found || contains(strings.Split(ids, ","), tagID)
+		}
+
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s *Server) list(w http.ResponseWriter, r *http.Request, resource string) {
+	query := r.URL.Query()
+
+	if resource == "entries" && query.Get("since") == "" &&
+		(query.Get("from") == "" || query.Get("to") == "") {
+		writeError(w, http.StatusBadRequest, "error.validation",
+			"Parameters from and to are required.",
+			toshl.FieldError{Field: "from", ErrorID: "error.validation.required"},
+			toshl.FieldError{Field: "to", ErrorID: "error.validation.required"})
+		return
+	}
+
+	page, _ := strconv.Atoi(query.Get("page"))
+	perPage, err := strconv.Atoi(query.Get("per_page"))
+	if err != nil || perPage <= 0 {
+		perPage = DefaultPerPage
+	}
+
+	if perPage > maxPerPage {
+		writeError(w, http.StatusBadRequest, "error.validation",
+			fmt.Sprintf("Parameter per_page must not exceed %d.", maxPerPage),
+			toshl.FieldError{Field: "per_page", ErrorID: "error.validation.max"})
+		return
+	}
+
+	var matching []object
+	for _, obj := range s.objects[resource] {
+		if matches(resource, obj, query) {
+			matching = append(matching, obj)
+		}
+	}
+
+	if resources[resource].ordered {
+		sort.SliceStable(matching, func(i, j int) bool {
+			return toInt(matching[i]["order"]) < toInt(matching[j]["order"])
+		})
+	}
+
+	start := min(page*perPage, len(matching))
+	end := min(start+perPage, len(matching))
+
+	if end < len(matching) {
+		next := url.Values{}
+		for key, values := range query {
+			next[key] = values
+		}
+		next.Set("page", strconv.Itoa(page+1))
+		next.Set("per_page", strconv.Itoa(perPage))
+
+		w.Header().Set("Link", fmt.Sprintf(`<%s/%s?%s>; rel="next"`,
+			s.URL, resource, next.Encode()))
+	}
+
+	writeJSON(w, http.StatusOK, append([]object{}, matching[start:end]...))
+}
+
+// toInt returns the integer held by v, set by the server or decoded
+// from JSON
+func toInt(v any) int {
+	switch v := v.(type) {
+	case int:
+		return v
+	case float64:
+		return int(v)
+	}
+
+	return 0
+}
+
+func (s *Server) get(w http.ResponseWriter, resource, id string) {
+	obj := s.find(resource, id)
+	if obj == nil || obj.deleted() {
+		writeNotFound(w, resource, id)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, obj)
+}
+
+// decode reads the JSON object of the request body and validates that
+// the required fields of resource are set
+func decode(
+	w http.ResponseWriter, r *http.Request, resource string,
+) (object, bool) {
+	var obj object
+	if err := json.NewDecoder(r.Body).Decode(&obj); err != nil {
+		writeError(w, http.StatusBadRequest, "error.request.invalid_json",
+			"Request body is not a valid JSON object.")
+		return nil, false
+	}
+
+	var fields []toshl.FieldError
+	for _, field := range resources[resource].required {
+		if value, ok := obj[field]; !ok || value == nil || value == "" {
+			fields = append(fields, toshl.FieldError{
+				Field:       field,
+				ErrorID:     "error.validation.required",
+				Description: fmt.Sprintf("Field %s is required.", field),
+			})
+		}
+	}
+
+	if len(fields) > 0 {
+		writeError(w, http.StatusBadRequest, "error.validation",
+			"Validation failed.", fields...)
+		return nil, false
+	}
+
+	return obj, true
+}
+
+func (s *Server) post(w http.ResponseWriter, r *http.Request, resource string) {
+	obj, ok := decode(w, r, resource)
+	if !ok {
+		return
+	}
+
+	id := s.create(resource, obj)
+
+	w.Header().Set("Location", fmt.Sprintf("%s/%s/%s", s.URL, resource, id))
+	w.WriteHeader(http.StatusCreated)
+}
+
+func (s *Server) put(
+	w http.ResponseWriter, r *http.Request, resource, id string,
+) {
+	stored := s.find(resource, id)
+	if stored == nil || stored.deleted() {
+		writeNotFound(w, resource, id)
+		return
+	}
+
+	obj, ok := decode(w, r, resource)
+	if !ok {
+		return
+	}
+
+	// Updates based on a stale copy are rejected, like the API does
+	if modified, ok := obj["modified"].(string); ok && modified != "" &&
+		modified != stored.str("modified") {
+		writeError(w, http.StatusConflict, "error.object.conflict",
+			fmt.Sprintf("Object with id %s was modified meanwhile.", id))
+		return
+	}
+
+	for key, value := range obj {
+		stored[key] = value
+	}
+
+	stored["id"] = id
+	stored["modified"] = s.modified()
+
+	writeJSON(w, http.StatusOK, stored)
+}
+
+func (s *Server) delete(w http.ResponseWriter, resource, id string) {
+	obj := s.find(resource, id)
+	if obj == nil || obj.deleted() {
+		writeNotFound(w, resource, id)
+		return
+	}
+
+	obj["deleted"] = true
+	obj["modified"] = s.modified()
+
+	if resource == "entries" {
+		s.updateSplit(obj, id, false)
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// splitParent returns the ID of the split parent of entry, if any
+func splitParent(entry object) string {
+	split, _ := entry["split"].(map[string]any)
+	parent, _ := split["parent"].(string)
+
+	return parent
+}
+
+// updateSplit adds or removes the split child entry having id to the
+// children of its parent, s.mu being held
+func (s *Server) updateSplit(entry object, id string, add bool) {
+	parent := s.find("entries", splitParent(entry))
+	if parent == nil {
+		return
+	}
+
+	split, _ := parent["split"].(map[string]any)
+	if split == nil {
+		split = map[string]any{}
+		parent["split"] = split
+	}
+
+	children, _ := split["children"].([]any)
+
+	var updated []any
+	for _, child := range children {
+		if child != id {
+			updated = append(updated, child)
+		}
+	}
+
+	if add {
+		updated = append(updated, id)
+	}
+
+	split["children"] = updated
+	parent["modified"] = s.modified()
+}
+
+func (s *Server) reorder(
+	w http.ResponseWriter, r *http.Request, resource string,
+) {
+	var params struct {
+		Order []string `json:"order"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&params); err != nil ||
+		len(params.Order) == 0 {
+		writeError(w, http.StatusBadRequest, "error.validation",
+			"Validation failed.", toshl.FieldError{
+				Field: "order", ErrorID: "error.validation.required",
+			})
+		return
+	}
+
+	for position, id := range params.Order {
+		obj := s.find(resource, id)
+		if obj == nil || obj.deleted() {
+			writeNotFound(w, resource, id)
+			return
+		}
+
+		obj["order"] = position
+		obj["modified"] = s.modified()
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// merge handles merges, whose payload lists the merged objects under the
+// resource name and the target under its singular form
+func (s *Server) merge(w http.ResponseWriter, r *http.Request, resource string) {
+	var params map[string]json.RawMessage
+	var merged []string
+	var target string
+
+	err := json.NewDecoder(r.Body).Decode(&params)
+	if err == nil {
+		err = json.Unmarshal(params[resource], &merged)
+	}
+	if err == nil {
+		err = json.Unmarshal(params[resources[resource].singular], &target)
+	}
+
+	if err != nil || len(merged) == 0 || target == "" {
+		writeError(w, http.StatusBadRequest, "error.validation",
+			"Validation failed.", toshl.FieldError{
+				Field: resource, ErrorID: "error.validation.required",
+			})
+		return
+	}
+
+	if obj := s.find(resource, target); obj == nil || obj.deleted() {
+		writeNotFound(w, resource, target)
+		return
+	}
+
+	key := resources[resource].mergeKey
+
+	for _, id := range merged {
+		if id == target {
+			continue
+		}
+
+		obj := s.find(resource, id)
+		if obj == nil || obj.deleted() {
+			writeNotFound(w, resource, id)
+			return
+		}
+
+		obj["deleted"] = true
+		obj["modified"] = s.modified()
+
+		for _, entry := range s.objects["entries"] {
+			if replaceReference(entry, key, id, target) {
+				entry["modified"] = s.modified()
+			}
+		}
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// replaceReference replaces the reference to id in the key field of entry
+// by target, and reports whether it did
+func replaceReference(entry object, key, id, target string) bool {
+	if list, ok := entry[key].([]any); ok {
+		replaced := false
+		for i, value := range list {
+			if value == id {
+				list[i] = target
+				replaced = true
+			}
+		}
+
+		return replaced
+	}
+
+	if entry.str(key) == id {
+		entry[key] = target
+		return true
+	}
+
+	return false
+}
+
+// serveImages handles image uploads, s.mu being held
+func (s *Server) serveImages(
+	w http.ResponseWriter, r *http.Request, parts []string,
+) {
+	switch {
+	case len(parts) == 1 && r.Method == http.MethodPost:
+		file, header, err := r.FormFile("file")
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "error.validation",
+				"Validation failed.", toshl.FieldError{
+					Field: "file", ErrorID: "error.validation.required",
+				})
+			return
+		}
+		defer file.Close()
+
+		data, err := io.ReadAll(file)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "error.request.invalid",
+				err.Error())
+			return
+		}
+
+		s.nextID++
+		id := strconv.Itoa(s.nextID)
+
+		image := object{
+			"id":       id,
+			"path":     fmt.Sprintf("%s/images/%s/download", s.URL, id),
+			"filename": header.Filename,
+			"type":     http.DetectContentType(data),
+			"status":   "uploaded",
+		}
+		s.objects["images"] = append(s.objects["images"], image)
+		s.files[id] = data
+
+		w.Header().Set("Location", fmt.Sprintf("%s/images/%s", s.URL, id))
+		writeJSON(w, http.StatusCreated, image)
+	case len(parts) == 2 && r.Method == http.MethodGet:
+		image := s.find("images", parts[1])
+		if image == nil {
+			writeNotFound(w, "images", parts[1])
+			return
+		}
+
+		writeJSON(w, http.StatusOK, image)
+	case len(parts) == 3 && parts[2] == "download" &&
+		r.Method == http.MethodGet:
+		data, ok := s.files[parts[1]]
+		if !ok {
+			writeNotFound(w, "images", parts[1])
+			return
+		}
+
+		w.Header().Set("Content-Type", http.DetectContentType(data))
+		w.Write(data)
+	default:
+		writeError(w, http.StatusNotFound, "error.route.not_found",
+			fmt.Sprintf("Route %s not found.", r.URL.Path))
+	}
+}
@@ -0,0 +1,241 @@
+package toshltest_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/Philanthropists/toshl-go"
+	"github.com/Philanthropists/toshl-go/toshltest"
+)
+
+func newServer(t *testing.T) (*toshltest.Server, *toshl.Client) {
+	t.Helper()
+
+	server := toshltest.NewServer()
+	t.Cleanup(server.Close)
+
+	return server, server.NewClient()
+}
+
+func testDate(year int, month time.Month, day int) toshl.Date {
+	return toshl.Date(time.Date(year, month, day, 0, 0, 0, 0, time.UTC))
+}
+
+func TestServerAccountFlow(t *testing.T) {
+	_, client := newServer(t)
+
+	id, err := client.CreateAccount(toshl.CreateAccountParams{
+		Name:     "Checking",
+		Currency: toshl.Currency{Code: "USD"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	account, err := client.GetAccount(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if account.Name != "Checking" {
+		t.Errorf("Name = %q, want %q", account.Name, "Checking")
+	}
+
+	account.Name = "Main"
+	if err := client.UpdateAccount(account); err != nil {
+		t.Fatal(err)
+	}
+
+	account, err = client.GetAccount(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if account.Name != "Main" {
+		t.Errorf("Name = %q, want %q", account.Name, "Main")
+	}
+
+	if err := client.DeleteAccount(account); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = client.GetAccount(id)
+	if !toshl.IsNotFound(err) {
+		t.Errorf("GetAccount after delete: err = %v, want not found", err)
+	}
+}
+
+func TestServerValidation(t *testing.T) {
+	_, client := newServer(t)
+
+	_, err := client.CreateAccount(toshl.CreateAccountParams{
+		Currency: toshl.Currency{Code: "USD"},
+	})
+	if !toshl.IsValidation(err) {
+		t.Fatalf("err = %v, want a validation error", err)
+	}
+
+	apiErr := err.(*toshl.APIError)
+	if len(apiErr.Fields) != 1 || apiErr.Fields[0].Field != "name" {
+		t.Errorf("Fields = %+v, want a single error on name", apiErr.Fields)
+	}
+}
+
+func TestServerPagination(t *testing.T) {
+	server, client := newServer(t)
+
+	for i := 0; i < 5; i++ {
+		_, err := server.Add("tags", toshl.Tag{Name: "tag", Type: "expense"})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	pages, count := 0, 0
+	for page, err := range client.TagPages(
+		context.Background(), &toshl.TagQueryParams{PerPage: 2},
+	) {
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		pages++
+		count += len(page.Items)
+	}
+
+	if pages != 3 || count != 5 {
+		t.Errorf("got %d tags in %d pages, want 5 in 3", count, pages)
+	}
+}
+
+func TestServerEntriesFilter(t *testing.T) {
+	server, client := newServer(t)
+
+	for _, date := range []string{"2024-01-10", "2024-02-10", "2024-03-10"} {
+		_, err := server.Add("entries", toshl.Entry{
+			Amount:   toshl.NewAmount(-500, 2),
+			Currency: toshl.Currency{Code: "USD"},
+			Date:     date,
+			Account:  "1",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	entries, err := client.Entries(&toshl.EntryQueryParams{
+		From: testDate(2024, time.February, 1),
+		To:   testDate(2024, time.March, 31),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(entries) != 2 {
+		t.Errorf("got %d entries, want 2", len(entries))
+	}
+}
+
+func TestServerMergeCategories(t *testing.T) {
+	server, client := newServer(t)
+
+	food := &toshl.Category{Name: "Food", Type: "expense"}
+	groceries := &toshl.Category{Name: "Groceries", Type: "expense"}
+	for _, category := range []*toshl.Category{food, groceries} {
+		if err := client.CreateCategory(category); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	entryID, err := server.Add("entries", toshl.Entry{
+		Amount:   toshl.NewAmount(-1000, 2),
+		Currency: toshl.Currency{Code: "USD"},
+		Date:     "2024-01-10",
+		Account:  "1",
+		Category: groceries.ID,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = client.MergeCategories(&toshl.CategoriesMergeParams{
+		Categories: []string{groceries.ID},
+		Category:   food.ID,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := server.Count("categories"); got != 1 {
+		t.Errorf("Count = %d, want 1", got)
+	}
+
+	entry, err := client.GetEntry(entryID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if entry.Category != food.ID {
+		t.Errorf("Category = %q, want %q", entry.Category, food.ID)
+	}
+}
+
+func TestServerReorderAccounts(t *testing.T) {
+	server, client := newServer(t)
+
+	var ids []string
+	for _, name := range []string{"A", "B", "C"} {
+		id, err := server.Add("accounts", toshl.CreateAccountParams{
+			Name:     name,
+			Currency: toshl.Currency{Code: "USD"},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		ids = append(ids, id)
+	}
+
+	err := client.ReorderAccounts(&toshl.AccountsOrderParams{
+		Order: []string{ids[2], ids[0], ids[1]},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	accounts, err := client.Accounts(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var names string
+	for _, account := range accounts {
+		names += account.Name
+	}
+
+	if names != "CAB" {
+		t.Errorf("order = %q, want %q", names, "CAB")
+	}
+}
+
+func TestServerToken(t *testing.T) {
+	server, _ := newServer(t)
+	server.Token = "secret"
+
+	client := toshl.NewClient("wrong", &toshl.RestHTTPClient{
+		BaseURL: server.URL,
+		Token:   "wrong",
+		Client:  http.DefaultClient,
+	})
+
+	_, err := client.Accounts(nil)
+	if !toshl.IsUnauthorized(err) {
+		t.Errorf("err = %v, want unauthorized", err)
+	}
+
+	if _, err := server.NewClient().Accounts(nil); err != nil {
+		t.Errorf("with the right token: %v", err)
+	}
+}
@@ -0,0 +1,91 @@
+package toshl
+
+import "time"
+
+// User represents the authenticated Toshl user
+type User struct {
+	ID        string                 `json:"id"`
+	Email     string                 `json:"email"`
+	FirstName string                 `json:"first_name"`
+	LastName  string                 `json:"last_name"`
+	Joined    string                 `json:"joined,omitempty"`
+	Modified  string                 `json:"modified,omitempty"`
+	Currency  UserCurrency           `json:"currency"`
+	StartDay  int                    `json:"start_day"`
+	WeekStart time.Weekday           `json:"week_start"`
+	Locale    string                 `json:"locale"`
+	Language  string                 `json:"language"`
+	Timezone  string                 `json:"timezone"`
+	Country   string                 `json:"country"`
+	Pro       *UserPro               `json:"pro,omitempty"`
+	Limits    *UserLimits            `json:"limits,omitempty"`
+	Extra     map[string]interface{} `json:"extra,omitempty"`
+}
+
+// UserCurrency represents the currency settings of a Toshl user
+type UserCurrency struct {
+	Main           string `json:"main"`
+	Update         string `json:"update,omitempty"`
+	UpdateAccounts bool   `json:"update_accounts"`
+}
+
+// UserPro represents the Pro subscription of a Toshl user
+type UserPro struct {
+	Level string `json:"level"`
+	Since string `json:"since"`
+	Until string `json:"until"`
+}
+
+// UserLimits represents the features available to a Toshl user
+type UserLimits struct {
+	Accounts  bool `json:"accounts"`
+	Budgets   bool `json:"budgets"`
+	Images    bool `json:"images"`
+	Import    bool `json:"import"`
+	Bank      bool `json:"bank"`
+	Repeats   bool `json:"repeats"`
+	Reminders bool `json:"reminders"`
+	Export    bool `json:"export"`
+	Planning  bool `json:"planning"`
+}
+
+// IsPro reports whether the user has a Pro subscription
+func (u *User) IsPro() bool {
+	return u.Pro != nil && u.Pro.Level != "" && u.Pro.Level != "free"
+}
+
+// Location returns the time zone of the user, UTC when it is unknown
+func (u *User) Location() *time.Location {
+	location, err := time.LoadLocation(u.Timezone)
+	if err != nil || u.Timezone == "" {
+		return time.UTC
+	}
+
+	return location
+}
+
+// MonthPeriod returns the first and last days of the user's month
+// containing t, months starting on StartDay. Start days past the end of a
+// month start it on its last day.
+func (u *User) MonthPeriod(t time.Time) (Date, Date) {
+	t = t.In(u.Location())
+
+	from := monthStart(t.Year(), t.Month(), u.StartDay, t.Location())
+	if t.Before(from) {
+		from = monthStart(t.Year(), t.Month()-1, u.StartDay, t.Location())
+	}
+
+	next := monthStart(from.Year(), from.Month()+1, u.StartDay, t.Location())
+
+	return Date(from), Date(next.AddDate(0, 0, -1))
+}
+
+// monthStart returns the startDay of a month, clamped to the month length
+func monthStart(
+	year int, month time.Month, startDay int, loc *time.Location,
+) time.Time {
+	first := time.Date(year, month, 1, 0, 0, 0, 0, loc)
+	days := first.AddDate(0, 1, -1).Day()
+
+	return first.AddDate(0, 0, min(max(startDay, 1), days)-1)
+}
@@ -0,0 +1,100 @@
+package toshl_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/Philanthropists/toshl-go"
+	"github.com/stretchr/testify/assert"
+)
+
+const userJSON = `{
+    "id": "42",
+    "email": "jane@example.com",
+    "first_name": "Jane",
+    "last_name": "Doe",
+    "joined": "2012-09-04T13:55:15Z",
+    "modified": "2013-06-27T14:14:03Z",
+    "currency": {
+        "main": "EUR",
+        "update": "auto",
+        "update_accounts": true
+    },
+    "start_day": 25,
+    "week_start": 1,
+    "locale": "en_GB",
+    "language": "en",
+    "timezone": "Europe/Ljubljana",
+    "country": "SI",
+    "pro": {
+        "level": "pro",
+        "since": "2013-01-01",
+        "until": "2014-01-01"
+    },
+    "limits": {
+        "accounts": true,
+        "budgets": true,
+        "images": true,
+        "import": true,
+        "bank": false,
+        "repeats": true,
+        "reminders": true,
+        "export": true,
+        "planning": true
+    }
+}`
+
+func TestUserDecode(t *testing.T) {
+	var user toshl.User
+
+	err := json.Unmarshal([]byte(userJSON), &user)
+	assert.Nil(t, err)
+	assert.Equal(t, "EUR", user.Currency.Main)
+	assert.Equal(t, 25, user.StartDay)
+	assert.Equal(t, time.Monday, user.WeekStart)
+	assert.True(t, user.IsPro())
+	assert.False(t, user.Limits.Bank)
+}
+
+func TestUserMonthPeriod(t *testing.T) {
+	user := toshl.User{StartDay: 25}
+
+	from, to := user.MonthPeriod(
+		time.Date(2021, 3, 10, 12, 0, 0, 0, time.UTC))
+	assert.Equal(t, "2021-02-25", from.String())
+	assert.Equal(t, "2021-03-24", to.String())
+
+	from, to = user.MonthPeriod(
+		time.Date(2021, 3, 25, 0, 0, 0, 0, time.UTC))
+	assert.Equal(t, "2021-03-25", from.String())
+	assert.Equal(t, "2021-04-24", to.String())
+
+	user.StartDay = 31
+	from, to = user.MonthPeriod(
+		time.Date(2021, 2, 28, 0, 0, 0, 0, time.UTC))
+	assert.Equal(t, "2021-02-28", from.String())
+	assert.Equal(t, "2021-03-30", to.String())
+
+	user.StartDay = 0
+	from, to = user.MonthPeriod(
+		time.Date(2021, 12, 31, 0, 0, 0, 0, time.UTC))
+	assert.Equal(t, "2021-12-01", from.String())
+	assert.Equal(t, "2021-12-31", to.String())
+}
+
+func TestClientMe(t *testing.T) {
+	c, requests := newTestClient(t, http.StatusOK, userJSON)
+
+	user, err := c.Me()
+	assert.Nil(t, err)
+	assert.Equal(t, "jane@example.com", user.Email)
+	assert.Equal(t, "/me", (*requests)[0].Path)
+
+	user.StartDay = 1
+	err = c.UpdateMe(user)
+	assert.Nil(t, err)
+	assert.Equal(t, "PUT", (*requests)[1].Method)
+	assert.Contains(t, (*requests)[1].Body, `"start_day":1`)
+}